@@ -14,6 +14,7 @@ import (
 	"io"
 	"lazymap"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"condense/template"
@@ -114,6 +115,43 @@ func (f *InputsFlag) Sources() []inputSource {
 	return f.sources
 }
 
+type LibraryFlag struct {
+	functions *rules.Functions
+	filenames []string
+}
+
+func NewLibraryFlag(functions *rules.Functions) LibraryFlag {
+	return LibraryFlag{
+		functions,
+		[]string{},
+	}
+}
+
+func (f LibraryFlag) String() string {
+	return fmt.Sprintf("%v", f.filenames)
+}
+
+func (f *LibraryFlag) Set(libraryFilename string) (err error) {
+	var inputStream io.Reader
+	var raw interface{}
+
+	if inputStream, err = os.Open(libraryFilename); err != nil {
+		return err
+	}
+
+	inputDecoder := json.NewDecoder(inputStream)
+	if err := inputDecoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	if err := f.functions.DefineAll(raw); err != nil {
+		return fmt.Errorf("Error loading library '%s': %s", libraryFilename, err)
+	}
+
+	f.filenames = append(f.filenames, libraryFilename)
+	return nil
+}
+
 type OutputWhat int
 
 const (
@@ -169,9 +207,30 @@ func (f *OutputWhatFlag) Set(input string) error {
 	return nil
 }
 
+func reportErrors() {
+	if r := recover(); r != nil {
+		if err, ok := r.(error); ok {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+
+		panic(r)
+	}
+}
+
 func main() {
+	defer reportErrors()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
 	templateRules := template.Rules{}
 	inputParameters := NewInputsFlag(&templateRules)
+	functions := rules.NewFunctions(100)
+	library := NewLibraryFlag(functions)
+	includes := rules.NewIncludes(cwd, 100)
 	var templateFilename string
 	var outputWhat OutputWhatFlag
 
@@ -183,6 +242,22 @@ func main() {
 		"parameters",
 		"File to use of input parameters (can be specified multiple times)")
 
+	flag.Var(&library,
+		"library",
+		"File of functions to expose to the template (can be specified multiple times)")
+
+	flag.IntVar(&functions.MaxDepth,
+		"max-call-depth", functions.MaxDepth,
+		"Maximum depth of nested Fn::Call invocations")
+
+	flag.BoolVar(&includes.RelativeToCwd,
+		"includes-relative-to-cwd", includes.RelativeToCwd,
+		"Resolve relative include paths against the working directory, rather than the including file")
+
+	flag.IntVar(&includes.MaxDepth,
+		"max-include-depth", includes.MaxDepth,
+		"Maximum depth of nested Fn::IncludeFile invocations")
+
 	flag.Var(&outputWhat,
 		"output",
 		"What to output after processing the Template")
@@ -190,7 +265,6 @@ func main() {
 	flag.Parse()
 
 	var jsonStream io.Reader
-	var err error
 
 	if templateFilename == "-" {
 		jsonStream = os.Stdin
@@ -198,12 +272,31 @@ func main() {
 		panic(err)
 	}
 
+	if templateFilename != "-" {
+		var templatePath string
+		if templatePath, err = filepath.Abs(templateFilename); err != nil {
+			panic(err)
+		}
+
+		if err = includes.Enter(templatePath, ""); err != nil {
+			panic(err)
+		}
+	}
+
 	dec := json.NewDecoder(jsonStream)
 	t := make(map[string]interface{})
 	if err := dec.Decode(&t); err != nil {
 		panic(err)
 	}
 
+	if definitions, ok := t["$functions"]; ok {
+		if err := functions.DefineAll(definitions); err != nil {
+			panic(err)
+		}
+
+		delete(t, "$functions")
+	}
+
 	sources := fallbackmap.FallbackMap{}
 	stack := deepstack.DeepStack{}
 
@@ -214,32 +307,90 @@ func main() {
 
 	stack.Push(&sources)
 
+	// only write Lambda zips when outputting the template which uses them
+	var lambdaCodeCreator rules.Creator = rules.DiscardCreator{}
+	if outputWhat.Get().what == OutputTemplate {
+		lambdaCodeCreator = rules.OSCreator{}
+	}
+
 	templateRules.AttachEarly(rules.ExcludeComments)
 	templateRules.AttachEarly(rules.MakeFnFor(&stack, &templateRules))
+	templateRules.AttachEarly(rules.MakeFnFilter(&stack, &templateRules))
+	templateRules.AttachEarly(rules.MakeFnForEachKey(&stack, &templateRules))
+	templateRules.AttachEarly(rules.MakeFnGroupBy(&stack, &templateRules))
+	templateRules.AttachEarly(rules.MakeFnReduce(&stack, &templateRules))
+	templateRules.AttachEarly(rules.MakeFnSortBy(&stack, &templateRules))
 	templateRules.AttachEarly(rules.MakeFnWith(&stack, &templateRules))
+	templateRules.AttachEarly(rules.MakeFnLet(&stack, &templateRules))
+	templateRules.AttachEarly(rules.MakeFnIfEarly(&templateRules))
 	templateRules.Attach(rules.FnAdd)
+	templateRules.Attach(rules.FnSubtract)
+	templateRules.Attach(rules.FnMultiply)
+	templateRules.Attach(rules.FnDivide)
+	templateRules.Attach(rules.FnMin)
+	templateRules.Attach(rules.FnMax)
+	templateRules.Attach(rules.FnFloor)
+	templateRules.Attach(rules.FnCeil)
 	templateRules.Attach(rules.FnIf)
 	templateRules.Attach(rules.FnAnd)
 	templateRules.Attach(rules.FnOr)
 	templateRules.Attach(rules.FnNot)
 	templateRules.Attach(rules.FnEquals)
+	templateRules.Attach(rules.FnLessThan)
+	templateRules.Attach(rules.FnLessThanOrEqual)
+	templateRules.Attach(rules.FnGreaterThan)
+	templateRules.Attach(rules.FnGreaterThanOrEqual)
+	templateRules.Attach(rules.FnCloudInitMultipart)
+	templateRules.Attach(rules.FnCoalesce)
 	templateRules.Attach(rules.FnConcat)
+	templateRules.Attach(rules.FnContains)
+	templateRules.Attach(rules.FnDifference)
+	templateRules.Attach(rules.FnFlatten)
 	templateRules.Attach(rules.FnFromEntries)
+	templateRules.Attach(rules.FnGet)
+	templateRules.Attach(rules.FnHash)
 	templateRules.Attach(rules.FnHasKey)
+	templateRules.Attach(rules.FnIntersection)
 	templateRules.Attach(rules.FnJoin)
 	templateRules.Attach(rules.FnKeys)
 	templateRules.Attach(rules.FnLength)
 	templateRules.Attach(rules.FnMerge)
 	templateRules.Attach(rules.FnMergeDeep)
 	templateRules.Attach(rules.FnMod)
+	templateRules.Attach(rules.FnOmit)
+	templateRules.Attach(rules.FnParseJson)
+	templateRules.Attach(rules.FnPick)
+	templateRules.Attach(rules.FnProduct)
+	templateRules.Attach(rules.FnRange)
+	templateRules.Attach(rules.FnRegexCapture)
+	templateRules.Attach(rules.FnRegexMatch)
+	templateRules.Attach(rules.FnRegexReplace)
+	templateRules.Attach(rules.FnRenameKeys)
+	templateRules.Attach(rules.FnReverse)
+	templateRules.Attach(rules.FnSlice)
+	templateRules.Attach(rules.FnSort)
 	templateRules.Attach(rules.FnSplit)
+	templateRules.Attach(rules.FnSwitch)
+	templateRules.Attach(rules.FnToBool)
 	templateRules.Attach(rules.FnToEntries)
+	templateRules.Attach(rules.FnToJsonString)
+	templateRules.Attach(rules.FnToList)
+	templateRules.Attach(rules.FnToNumber)
+	templateRules.Attach(rules.FnToString)
+	templateRules.Attach(rules.FnTypeOf)
+	templateRules.Attach(rules.FnUnion)
 	templateRules.Attach(rules.FnUnique)
+	templateRules.Attach(rules.FnZip)
 	templateRules.Attach(rules.MakeFnGetAtt(&stack, &templateRules))
 	templateRules.Attach(rules.MakeRef(&stack, &templateRules))
 	templateRules.Attach(rules.MakeFnHasRef(&stack))
-	templateRules.Attach(rules.MakeFnIncludeFile(vfs.OS("/"), &templateRules))
-	templateRules.Attach(rules.MakeFnIncludeFileRaw(vfs.OS("/")))
+	templateRules.Attach(rules.MakeFnCall(&stack, functions, &templateRules))
+	templateRules.Attach(rules.MakeFnIncludeFile(vfs.OS("/"), includes, &templateRules))
+	templateRules.Attach(rules.MakeFnIncludeFileRaw(vfs.OS("/"), includes))
+	templateRules.Attach(rules.MakeFnIncludeGlob(vfs.OS("/"), includes, &templateRules))
+	templateRules.Attach(rules.MakeFnIncludeLambdaCode(vfs.OS("/"), lambdaCodeCreator, includes))
+	templateRules.Attach(rules.MakeFnIncludeTemplate(vfs.OS("/"), includes, &templateRules))
+	templateRules.Attach(rules.MakeFnFileHash(vfs.OS("/"), includes))
 	templateRules.Attach(rules.ReduceConditions)
 
 	// First Pass (to collect Parameter names)
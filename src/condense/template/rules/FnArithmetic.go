@@ -0,0 +1,157 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+)
+
+func numericArgs(node interface{}, fnName string) ([]float64, bool) {
+	argsInterface, ok := singleKey(node, fnName)
+	if !ok {
+		return nil, false
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return nil, false
+	}
+
+	numbers := []float64{}
+	for _, arg := range args {
+		var argFloat float64
+		if argFloat, ok = arg.(float64); !ok {
+			return nil, false
+		}
+
+		numbers = append(numbers, argFloat)
+	}
+
+	return numbers, true
+}
+
+func FnSubtract(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := numericArgs(node, "Fn::Subtract")
+	if !ok || len(args) != 2 {
+		return key, node //passthru
+	}
+
+	return key, interface{}(args[0] - args[1])
+}
+
+func FnMultiply(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := numericArgs(node, "Fn::Multiply")
+	if !ok {
+		return key, node //passthru
+	}
+
+	product := float64(1)
+	for _, arg := range args {
+		product *= arg
+	}
+
+	return key, interface{}(product)
+}
+
+func FnDivide(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := numericArgs(node, "Fn::Divide")
+	if !ok || len(args) != 2 {
+		return key, node //passthru
+	}
+
+	if args[1] == 0 {
+		panic(fmt.Errorf("Division by zero in Fn::Divide at '%s'", formatPath(path)))
+	}
+
+	return key, interface{}(args[0] / args[1])
+}
+
+func FnMin(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := numericArgs(node, "Fn::Min")
+	if !ok || len(args) < 1 {
+		return key, node //passthru
+	}
+
+	min := args[0]
+	for _, arg := range args[1:] {
+		min = math.Min(min, arg)
+	}
+
+	return key, interface{}(min)
+}
+
+func FnMax(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := numericArgs(node, "Fn::Max")
+	if !ok || len(args) < 1 {
+		return key, node //passthru
+	}
+
+	max := args[0]
+	for _, arg := range args[1:] {
+		max = math.Max(max, arg)
+	}
+
+	return key, interface{}(max)
+}
+
+func FnFloor(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::Floor")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var argFloat float64
+	if argFloat, ok = argInterface.(float64); !ok {
+		return key, node //passthru
+	}
+
+	return key, interface{}(math.Floor(argFloat))
+}
+
+func FnCeil(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::Ceil")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var argFloat float64
+	if argFloat, ok = argInterface.(float64); !ok {
+		return key, node //passthru
+	}
+
+	return key, interface{}(math.Ceil(argFloat))
+}
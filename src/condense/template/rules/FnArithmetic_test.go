@@ -0,0 +1,144 @@
+package rules
+
+import (
+	"condense/template"
+	"reflect"
+	"testing"
+)
+
+func TestFnArithmetic_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnSubtract, "Fn::Subtract", t)
+	testRule_Passthru_NonMatching(FnMultiply, "Fn::Multiply", t)
+	testRule_Passthru_NonMatching(FnDivide, "Fn::Divide", t)
+	testRule_Passthru_NonMatching(FnMin, "Fn::Min", t)
+	testRule_Passthru_NonMatching(FnMax, "Fn::Max", t)
+	testRule_Passthru_NonMatching(FnFloor, "Fn::Floor", t)
+	testRule_Passthru_NonMatching(FnCeil, "Fn::Ceil", t)
+}
+
+func TestFnArithmetic_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnSubtract, "Fn::Subtract", t)
+	testRule_Passthru_NonArgsList(FnMultiply, "Fn::Multiply", t)
+	testRule_Passthru_NonArgsList(FnDivide, "Fn::Divide", t)
+	testRule_Passthru_NonArgsList(FnMin, "Fn::Min", t)
+	testRule_Passthru_NonArgsList(FnMax, "Fn::Max", t)
+	testRule_Passthru_NonArgsList(FnFloor, "Fn::Floor", t)
+	testRule_Passthru_NonArgsList(FnCeil, "Fn::Ceil", t)
+}
+
+func TestFnArithmetic_Passthru_BadArguments(t *testing.T) {
+	rules := map[string]template.Rule{
+		"Fn::Subtract": FnSubtract,
+		"Fn::Multiply": FnMultiply,
+		"Fn::Divide":   FnDivide,
+		"Fn::Min":      FnMin,
+		"Fn::Max":      FnMax,
+	}
+
+	inputs := map[string][]interface{}{
+		"Fn::Subtract": []interface{}{
+			[]interface{}{float64(1)},
+			[]interface{}{float64(1), float64(2), float64(3)},
+			[]interface{}{float64(1), "non-number"},
+		},
+		"Fn::Multiply": []interface{}{
+			[]interface{}{float64(1), "non-number"},
+		},
+		"Fn::Divide": []interface{}{
+			[]interface{}{float64(1)},
+			[]interface{}{"non-number", float64(1)},
+		},
+		"Fn::Min": []interface{}{
+			[]interface{}{},
+			[]interface{}{float64(1), map[string]interface{}{"Ref": "Unresolved"}},
+		},
+		"Fn::Max": []interface{}{
+			[]interface{}{},
+			[]interface{}{float64(1), "non-number"},
+		},
+	}
+
+	for fnName, fnInputs := range inputs {
+		for _, input := range fnInputs {
+			input := interface{}(map[string]interface{}{
+				fnName: input,
+			})
+
+			newKey, newNode := rules[fnName]([]interface{}{"x", "y"}, input)
+			if newKey != "y" {
+				t.Fatalf("%s modified the path (%v instead of %v)", fnName, newKey, "y")
+			}
+
+			if !reflect.DeepEqual(newNode, input) {
+				t.Fatalf("%s with bad arguments %v modified the data (%v instead of %v)", fnName, input, newNode, input)
+			}
+		}
+	}
+}
+
+func TestFnDivide_Panic_DivisionByZero(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::Divide": []interface{}{float64(1), float64(0)},
+	})
+
+	_, _ = FnDivide([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnDivide by zero did not panic")
+}
+
+func TestFnArithmetic_Basic(t *testing.T) {
+	rules := []template.Rule{
+		FnSubtract,
+		FnMultiply,
+		FnMultiply,
+		FnDivide,
+		FnMin,
+		FnMax,
+		FnFloor,
+		FnFloor,
+		FnCeil,
+		FnCeil,
+	}
+
+	inputs := []interface{}{
+		map[string]interface{}{"Fn::Subtract": []interface{}{float64(8080), float64(80)}},
+		map[string]interface{}{"Fn::Multiply": []interface{}{float64(2), float64(3), float64(4)}},
+		map[string]interface{}{"Fn::Multiply": []interface{}{}},
+		map[string]interface{}{"Fn::Divide": []interface{}{float64(10), float64(4)}},
+		map[string]interface{}{"Fn::Min": []interface{}{float64(3), float64(1), float64(2)}},
+		map[string]interface{}{"Fn::Max": []interface{}{float64(3), float64(1), float64(2)}},
+		map[string]interface{}{"Fn::Floor": float64(2.5)},
+		map[string]interface{}{"Fn::Floor": float64(-2.5)},
+		map[string]interface{}{"Fn::Ceil": float64(2.5)},
+		map[string]interface{}{"Fn::Ceil": float64(2)},
+	}
+
+	expected := []interface{}{
+		float64(8000),
+		float64(24),
+		float64(1),
+		float64(2.5),
+		float64(1),
+		float64(3),
+		float64(2),
+		float64(-3),
+		float64(3),
+		float64(2),
+	}
+
+	for i, input := range inputs {
+		newKey, newNode := rules[i]([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("Arithmetic modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("Arithmetic of %v did not return the expected result (%T(%v) instead of %T(%v))", input, newNode, newNode, expected[i], expected[i])
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"condense/template"
 	"reflect"
 )
 
@@ -36,6 +37,55 @@ func FnIf(path []interface{}, node interface{}) (interface{}, interface{}) {
 	return key, args[2]
 }
 
+// MakeFnIfEarly selects the branch of an Fn::If before either branch is
+// processed, whenever the condition can be resolved up front. This allows
+// the unselected branch to contain (for example) the recursive Fn::Call
+// which would otherwise never terminate. The selected branch is left to be
+// processed (once) in place of the Fn::If.
+func MakeFnIfEarly(templateRules *template.Rules) template.Rule {
+	eachEarly := templateRules.MakeEachEarly()
+
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		argsInterface, ok := singleKey(node, "Fn::If")
+		if !ok {
+			return key, node //passthru
+		}
+
+		var args []interface{}
+		if args, ok = argsInterface.([]interface{}); !ok {
+			return key, node //passthru
+		}
+
+		if len(args) != 3 {
+			return key, node //passthru
+		}
+
+		_, conditionNode := template.Walk(path, args[0], templateRules)
+
+		var condition bool
+		if condition, ok = conditionNode.(bool); !ok {
+			//left for FnIf, once the branches are processed, but without
+			//processing the condition a second time
+			return key, interface{}(map[string]interface{}{
+				"Fn::If": []interface{}{template.Processed{Node: conditionNode}, args[1], args[2]},
+			})
+		}
+
+		//the Early rules (eg: Fn::For) still apply to the selected branch,
+		//as they would have had it been in place of the Fn::If
+		if condition {
+			return eachEarly(path, args[1])
+		}
+
+		return eachEarly(path, args[2])
+	}
+}
+
 func FnEquals(path []interface{}, node interface{}) (interface{}, interface{}) {
 	key := interface{}(nil)
 	if len(path) > 0 {
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"condense/template"
 	"reflect"
 	"testing"
 )
@@ -371,3 +372,124 @@ func TestFnNot_Basic(t *testing.T) {
 		}
 	}
 }
+
+func TestMakeFnIfEarly_Basic(t *testing.T) {
+	templateRules := template.Rules{}
+	templateRules.Attach(FnEquals)
+	templateRules.Attach(func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		if _, ok := singleKey(node, "Fn::Fail"); ok {
+			t.Fatalf("MakeFnIfEarly processed the unselected branch")
+		}
+
+		return path[len(path)-1], node
+	})
+
+	fnIfEarly := MakeFnIfEarly(&templateRules)
+
+	inputs := []interface{}{
+		[]interface{}{
+			map[string]interface{}{"Fn::Equals": []interface{}{"a", "a"}},
+			map[string]interface{}{"Fn::Equals": []interface{}{"b", "b"}},
+			map[string]interface{}{"Fn::Fail": "unselected"},
+		},
+		[]interface{}{
+			false,
+			map[string]interface{}{"Fn::Fail": "unselected"},
+			"selected",
+		},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"Fn::Equals": []interface{}{"b", "b"}},
+		"selected",
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::If": input,
+		})
+
+		newKey, newNode := fnIfEarly([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("MakeFnIfEarly modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("MakeFnIfEarly of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestMakeFnIfEarly_Passthru_Unresolved(t *testing.T) {
+	fnIfEarly := MakeFnIfEarly(&template.Rules{})
+
+	input := interface{}(map[string]interface{}{
+		"Fn::If": []interface{}{map[string]interface{}{"Ref": "Unresolved"}, "a", "b"},
+	})
+
+	newKey, newNode := fnIfEarly([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("MakeFnIfEarly modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	expected := interface{}(map[string]interface{}{
+		"Fn::If": []interface{}{
+			template.Processed{Node: map[string]interface{}{"Ref": "Unresolved"}},
+			"a",
+			"b",
+		},
+	})
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("MakeFnIfEarly with an unresolved condition did not return the expected result (%#v instead of %#v)", newNode, expected)
+	}
+}
+
+func TestMakeFnIfEarly_ProcessedOnce(t *testing.T) {
+	counts := map[string]int{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(MakeFnIfEarly(&templateRules))
+	templateRules.Attach(FnAdd)
+	templateRules.Attach(FnParseJson)
+	templateRules.Attach(FnIf)
+	templateRules.Attach(func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		if name, ok := singleKey(node, "Fn::Count"); ok {
+			counts[name.(string)]++
+		}
+
+		return path[len(path)-1], node
+	})
+
+	inputs := []interface{}{
+		map[string]interface{}{"Fn::If": []interface{}{
+			true,
+			map[string]interface{}{"Fn::ParseJson": "{\"Fn::Add\": [1, 2]}"},
+			"no",
+		}},
+		map[string]interface{}{"Fn::If": []interface{}{
+			map[string]interface{}{"Fn::Count": "condition"},
+			"yes",
+			"no",
+		}},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"Fn::Add": []interface{}{float64(1), float64(2)}},
+		map[string]interface{}{"Fn::If": []interface{}{
+			map[string]interface{}{"Fn::Count": "condition"},
+			"yes",
+			"no",
+		}},
+	}
+
+	for i, input := range inputs {
+		_, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("MakeFnIfEarly of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+
+	if counts["condition"] != 1 {
+		t.Fatalf("MakeFnIfEarly processed an unresolved condition %d times instead of once", counts["condition"])
+	}
+}
@@ -0,0 +1,170 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fallbackmap"
+	"fmt"
+	"reflect"
+)
+
+type function struct {
+	params []string
+	body   interface{}
+}
+
+// Functions is a library of named, parameterized templates, invoked via
+// Fn::Call.
+type Functions struct {
+	MaxDepth    int
+	definitions map[string]function
+	depth       int
+	calls       map[string][]map[string]interface{}
+}
+
+func NewFunctions(maxDepth int) *Functions {
+	return &Functions{
+		MaxDepth:    maxDepth,
+		definitions: make(map[string]function),
+		calls:       make(map[string][]map[string]interface{}),
+	}
+}
+
+// derivesUnresolved detects a recursive call whose arguments are built
+// from the unresolved arguments of the enclosing call of the same function
+// (eg: n - 1, where n is not yet bound). Such a recursion cannot end until
+// they are resolved. Unresolved arguments which are passed along unchanged
+// (eg: a Ref to a CloudFormation parameter) do not prevent a recursion
+// from ending.
+func (functions *Functions) derivesUnresolved(name string, callArgs map[string]interface{}) bool {
+	enclosingCalls := functions.calls[name]
+	if len(enclosingCalls) == 0 {
+		return false
+	}
+
+	enclosingArgs := enclosingCalls[len(enclosingCalls)-1]
+	for param, value := range callArgs {
+		enclosingValue := enclosingArgs[param]
+		if isUnresolved(value) && isUnresolved(enclosingValue) && !reflect.DeepEqual(value, enclosingValue) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Define adds a function to the library. The definition must be in the
+// form {"Fn::Define": [["param", ...], body]}.
+func (functions *Functions) Define(name string, definition interface{}) error {
+	raw, ok := singleKey(definition, "Fn::Define")
+	if !ok {
+		return fmt.Errorf("Function '%s' is not defined via Fn::Define", name)
+	}
+
+	var args []interface{}
+	if args, ok = raw.([]interface{}); !ok || len(args) != 2 {
+		return fmt.Errorf("Fn::Define of '%s' must be in the form [[params], body]", name)
+	}
+
+	var paramsInterface []interface{}
+	if paramsInterface, ok = args[0].([]interface{}); !ok {
+		return fmt.Errorf("Fn::Define of '%s' does not have a list of params", name)
+	}
+
+	params := []string{}
+	for _, paramInterface := range paramsInterface {
+		var param string
+		if param, ok = paramInterface.(string); !ok {
+			return fmt.Errorf("Fn::Define of '%s' has a non-string param %#v", name, paramInterface)
+		}
+
+		params = append(params, param)
+	}
+
+	functions.definitions[name] = function{params: params, body: args[1]}
+	return nil
+}
+
+// DefineAll adds each entry of a "$functions" section (or library file) to
+// the library.
+func (functions *Functions) DefineAll(definitions interface{}) error {
+	definitionsMap, ok := definitions.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Functions must be defined as an object of {\"name\": {\"Fn::Define\": ...}}")
+	}
+
+	for name, definition := range definitionsMap {
+		if err := functions.Define(name, definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func MakeFnCall(sources *deepstack.DeepStack, functions *Functions, templateRules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		argsInterface, ok := singleKey(node, "Fn::Call")
+		if !ok {
+			return key, node //passthru
+		}
+
+		var args []interface{}
+		if args, ok = argsInterface.([]interface{}); !ok {
+			return key, node //passthru
+		}
+
+		if len(args) != 1 && len(args) != 2 {
+			return key, node //passthru
+		}
+
+		var name string
+		if name, ok = args[0].(string); !ok {
+			return key, node //passthru
+		}
+
+		callArgs := map[string]interface{}{}
+		if len(args) == 2 {
+			if callArgs, ok = args[1].(map[string]interface{}); !ok {
+				return key, node //passthru
+			}
+		}
+
+		var definition function
+		if definition, ok = functions.definitions[name]; !ok {
+			panic(fmt.Errorf("Fn::Call at '%s' of undefined function '%s'", formatPath(path), name))
+		}
+
+		for _, param := range definition.params {
+			if _, ok = callArgs[param]; !ok {
+				panic(fmt.Errorf("Fn::Call at '%s' of function '%s' is missing the argument '%s'", formatPath(path), name, param))
+			}
+		}
+
+		if functions.derivesUnresolved(name, callArgs) {
+			// Arguments which are not yet bound (eg: while the templates of
+			// an Fn::For are first collected) cannot end a recursion, so
+			// leave the call to be made once they are.
+			return key, node //passthru
+		}
+
+		if functions.depth >= functions.MaxDepth {
+			panic(fmt.Errorf("Fn::Call at '%s' of function '%s' exceeded the maximum call depth of %d", formatPath(path), name, functions.MaxDepth))
+		}
+
+		functions.depth++
+		functions.calls[name] = append(functions.calls[name], callArgs)
+		sources.Push(fallbackmap.DeepMap(callArgs))
+		key, generated := template.Walk(path, definition.body, templateRules)
+		sources.PopDiscard()
+		functions.calls[name] = functions.calls[name][:len(functions.calls[name])-1]
+		functions.depth--
+
+		return key, interface{}(generated)
+	}
+}
@@ -0,0 +1,322 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"reflect"
+	"testing"
+)
+
+func testMakeFnCall(functions *Functions) template.Rule {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	fnCall := MakeFnCall(&stack, functions, &templateRules)
+	templateRules.Attach(fnCall)
+
+	return fnCall
+}
+
+func TestFnCall_Passthru_NonMatching(t *testing.T) {
+	fnCall := testMakeFnCall(NewFunctions(10))
+	testRule_Passthru_NonMatching(fnCall, "Fn::Call", t)
+}
+
+func TestFnCall_Passthru_NonArgsList(t *testing.T) {
+	fnCall := testMakeFnCall(NewFunctions(10))
+	testRule_Passthru_NonArgsList(fnCall, "Fn::Call", t)
+}
+
+func TestFnCall_Passthru_BadArguments(t *testing.T) {
+	fnCall := testMakeFnCall(NewFunctions(10))
+
+	inputs := [][]interface{}{
+		[]interface{}{},
+		[]interface{}{"aFunction", map[string]interface{}{}, "tooMany"},
+		[]interface{}{float64(1), map[string]interface{}{}},
+		[]interface{}{"aFunction", "nonMap"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Call": input,
+		})
+
+		newKey, newNode := fnCall([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnCall modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnCall with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFunctions_Define_Errors(t *testing.T) {
+	definitions := []interface{}{
+		"nonDefinition",
+		map[string]interface{}{"Fn::Define": "nonList"},
+		map[string]interface{}{"Fn::Define": []interface{}{[]interface{}{"a"}}},
+		map[string]interface{}{"Fn::Define": []interface{}{"nonList", "aBody"}},
+		map[string]interface{}{"Fn::Define": []interface{}{[]interface{}{1}, "aBody"}},
+	}
+
+	for _, definition := range definitions {
+		if err := NewFunctions(10).Define("aFunction", definition); err == nil {
+			t.Fatalf("Defining a function as %#v did not return an error", definition)
+		}
+	}
+
+	if err := NewFunctions(10).DefineAll("nonMap"); err == nil {
+		t.Fatalf("Defining functions from a non-map did not return an error")
+	}
+}
+
+func TestFnCall_Panic_Undefined(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnCall := testMakeFnCall(NewFunctions(10))
+	input := interface{}(map[string]interface{}{
+		"Fn::Call": []interface{}{"undefined"},
+	})
+
+	_, _ = fnCall([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnCall of an undefined function did not panic")
+}
+
+func TestFnCall_Panic_MissingArgument(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	functions := NewFunctions(10)
+	functions.Define("aFunction", map[string]interface{}{
+		"Fn::Define": []interface{}{[]interface{}{"name"}, "aBody"},
+	})
+
+	fnCall := testMakeFnCall(functions)
+	input := interface{}(map[string]interface{}{
+		"Fn::Call": []interface{}{"aFunction", map[string]interface{}{}},
+	})
+
+	_, _ = fnCall([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnCall without a required argument did not panic")
+}
+
+func TestFnCall_Panic_Recursion(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	functions := NewFunctions(10)
+	functions.Define("recurse", map[string]interface{}{
+		"Fn::Define": []interface{}{
+			[]interface{}{},
+			map[string]interface{}{"Fn::Call": []interface{}{"recurse"}},
+		},
+	})
+
+	fnCall := testMakeFnCall(functions)
+	input := interface{}(map[string]interface{}{
+		"Fn::Call": []interface{}{"recurse"},
+	})
+
+	_, _ = fnCall([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnCall with unbounded recursion did not panic")
+}
+
+func TestFnCall_Basic(t *testing.T) {
+	functions := NewFunctions(10)
+	err := functions.DefineAll(map[string]interface{}{
+		"makeQueue": map[string]interface{}{
+			"Fn::Define": []interface{}{
+				[]interface{}{"name"},
+				map[string]interface{}{
+					"Type":       "AWS::SQS::Queue",
+					"Properties": map[string]interface{}{"QueueName": map[string]interface{}{"Ref": "name"}},
+				},
+			},
+		},
+		"makeQueues": map[string]interface{}{
+			"Fn::Define": []interface{}{
+				[]interface{}{"first", "second"},
+				[]interface{}{
+					map[string]interface{}{"Fn::Call": []interface{}{"makeQueue", map[string]interface{}{"name": map[string]interface{}{"Ref": "first"}}}},
+					map[string]interface{}{"Fn::Call": []interface{}{"makeQueue", map[string]interface{}{"name": map[string]interface{}{"Ref": "second"}}}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Defining functions returned an error: %s", err)
+	}
+
+	fnCall := testMakeFnCall(functions)
+
+	queue := func(name string) interface{} {
+		return map[string]interface{}{
+			"Type":       "AWS::SQS::Queue",
+			"Properties": map[string]interface{}{"QueueName": name},
+		}
+	}
+
+	inputs := []interface{}{
+		[]interface{}{"makeQueue", map[string]interface{}{"name": "x"}},
+		[]interface{}{"makeQueues", map[string]interface{}{"first": "a", "second": "b"}},
+	}
+
+	expected := []interface{}{
+		queue("x"),
+		[]interface{}{queue("a"), queue("b")},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Call": input,
+		})
+
+		newKey, newNode := fnCall([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnCall modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnCall of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnCall_TerminatingRecursion(t *testing.T) {
+	functions := NewFunctions(10)
+	err := functions.DefineAll(map[string]interface{}{
+		"countdown": map[string]interface{}{
+			"Fn::Define": []interface{}{
+				[]interface{}{"n"},
+				map[string]interface{}{"Fn::If": []interface{}{
+					map[string]interface{}{"Fn::LessThanOrEqual": []interface{}{map[string]interface{}{"Ref": "n"}, float64(0)}},
+					[]interface{}{},
+					map[string]interface{}{"Fn::Concat": []interface{}{
+						[]interface{}{map[string]interface{}{"Ref": "n"}},
+						map[string]interface{}{"Fn::Call": []interface{}{"countdown", map[string]interface{}{
+							"n": map[string]interface{}{"Fn::Subtract": []interface{}{map[string]interface{}{"Ref": "n"}, float64(1)}},
+						}}},
+					}},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Defining functions returned an error: %s", err)
+	}
+
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(MakeFnFor(&stack, &templateRules))
+	templateRules.AttachEarly(MakeFnIfEarly(&templateRules))
+	templateRules.Attach(FnSubtract)
+	templateRules.Attach(FnLessThanOrEqual)
+	templateRules.Attach(FnConcat)
+	templateRules.Attach(FnIf)
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(MakeFnCall(&stack, functions, &templateRules))
+
+	inputs := []interface{}{
+		map[string]interface{}{"Fn::Call": []interface{}{"countdown", map[string]interface{}{"n": float64(3)}}},
+		map[string]interface{}{"Fn::For": []interface{}{
+			"$n",
+			[]interface{}{float64(1), float64(2)},
+			map[string]interface{}{"Fn::Call": []interface{}{"countdown", map[string]interface{}{"n": map[string]interface{}{"Ref": "$n"}}}},
+		}},
+	}
+
+	expected := []interface{}{
+		[]interface{}{float64(3), float64(2), float64(1)},
+		[]interface{}{
+			[]interface{}{float64(1)},
+			[]interface{}{float64(2), float64(1)},
+		},
+	}
+
+	for i, input := range inputs {
+		newKey, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+		if newKey != "y" {
+			t.Fatalf("FnCall modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnCall of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnCall_UnresolvedRecursionBounded(t *testing.T) {
+	functions := NewFunctions(100)
+	err := functions.DefineAll(map[string]interface{}{
+		"tree": map[string]interface{}{
+			"Fn::Define": []interface{}{
+				[]interface{}{"n"},
+				map[string]interface{}{"Fn::If": []interface{}{
+					map[string]interface{}{"Fn::LessThanOrEqual": []interface{}{map[string]interface{}{"Ref": "n"}, float64(0)}},
+					float64(1),
+					map[string]interface{}{"Fn::Add": []interface{}{
+						map[string]interface{}{"Fn::Call": []interface{}{"tree", map[string]interface{}{
+							"n": map[string]interface{}{"Fn::Subtract": []interface{}{map[string]interface{}{"Ref": "n"}, float64(1)}},
+						}}},
+						map[string]interface{}{"Fn::Call": []interface{}{"tree", map[string]interface{}{
+							"n": map[string]interface{}{"Fn::Subtract": []interface{}{map[string]interface{}{"Ref": "n"}, float64(1)}},
+						}}},
+					}},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Defining functions returned an error: %s", err)
+	}
+
+	expansions := 0
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(MakeFnFor(&stack, &templateRules))
+	templateRules.AttachEarly(MakeFnIfEarly(&templateRules))
+	templateRules.Attach(func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		if _, ok := singleKey(node, "Fn::LessThanOrEqual"); ok {
+			expansions++
+		}
+
+		return path[len(path)-1], node
+	})
+	templateRules.Attach(FnAdd)
+	templateRules.Attach(FnSubtract)
+	templateRules.Attach(FnLessThanOrEqual)
+	templateRules.Attach(FnIf)
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(MakeFnCall(&stack, functions, &templateRules))
+
+	input := interface{}(map[string]interface{}{"Fn::For": []interface{}{
+		"$n",
+		[]interface{}{float64(1), float64(2), float64(3)},
+		map[string]interface{}{"Fn::Call": []interface{}{"tree", map[string]interface{}{"n": map[string]interface{}{"Ref": "$n"}}}},
+	}})
+
+	expected := []interface{}{float64(2), float64(4), float64(8)}
+
+	_, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnCall of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected)
+	}
+
+	// 1 + 3 + 7 + 15 expansions once bound, and only 1 while unbound
+	if expansions > 27 {
+		t.Fatalf("FnCall expanded an unbound recursion %d times", expansions)
+	}
+}
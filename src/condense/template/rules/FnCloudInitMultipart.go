@@ -0,0 +1,165 @@
+package rules
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const cloudInitBoundary = "==CONDENSE-MULTIPART-BOUNDARY=="
+
+type cloudInitPart struct {
+	contentType string
+	filename    string
+	content     interface{}
+}
+
+// cloudInitMultipartArgs accepts either a list of parts, or an object of the
+// form {"parts": [parts], "gzip": bool, "base64": bool}, where each part is
+// in the form {"contentType": type, "content": content, "filename": name},
+// and filename is optional.
+func cloudInitMultipartArgs(argsInterface interface{}) (parts []cloudInitPart, gzipped bool, base64ed bool, ok bool) {
+	partsInterface := argsInterface
+	if args, isMap := argsInterface.(map[string]interface{}); isMap {
+		if isIntrinsic(args) {
+			return nil, false, false, false
+		}
+
+		for argKey, argValue := range args {
+			switch argKey {
+			case "parts":
+				partsInterface, ok = argValue, true
+			case "gzip":
+				gzipped, ok = argValue.(bool)
+			case "base64":
+				base64ed, ok = argValue.(bool)
+			default:
+				ok = false
+			}
+
+			if !ok {
+				return nil, false, false, false
+			}
+		}
+	}
+
+	var partsList []interface{}
+	if partsList, ok = partsInterface.([]interface{}); !ok || len(partsList) == 0 {
+		return nil, false, false, false
+	}
+
+	for i, partInterface := range partsList {
+		var partMap map[string]interface{}
+		if partMap, ok = partInterface.(map[string]interface{}); !ok {
+			return nil, false, false, false
+		}
+
+		part := cloudInitPart{filename: fmt.Sprintf("part-%03d", i+1)}
+		for partKey, partValue := range partMap {
+			switch partKey {
+			case "contentType":
+				part.contentType, ok = partValue.(string)
+			case "filename":
+				part.filename, ok = partValue.(string)
+			case "content":
+				part.content = partValue
+				_, isString := partValue.(string)
+				ok = isString || isIntrinsic(partValue)
+			default:
+				ok = false
+			}
+
+			if !ok {
+				return nil, false, false, false
+			}
+		}
+
+		if part.contentType == "" || part.content == nil {
+			return nil, false, false, false
+		}
+
+		parts = append(parts, part)
+	}
+
+	return parts, gzipped, base64ed, true
+}
+
+// contentPieces splits content into pieces suitable for joinPieces,
+// unwrapping any Fn::Join with an empty delimiter.
+func contentPieces(content interface{}) []interface{} {
+	if joinArgs, ok := singleKey(content, "Fn::Join"); ok {
+		if args, ok := joinArgs.([]interface{}); ok && len(args) == 2 && isEqualString(args[0], "") {
+			if list, ok := args[1].([]interface{}); ok {
+				pieces := []interface{}{}
+				for _, item := range list {
+					pieces = append(pieces, contentPieces(item)...)
+				}
+				return pieces
+			}
+		}
+	}
+
+	return []interface{}{content}
+}
+
+func FnCloudInitMultipart(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::CloudInitMultipart")
+	if !ok {
+		return key, node //passthru
+	}
+
+	parts, gzipped, base64ed, ok := cloudInitMultipartArgs(argsInterface)
+	if !ok {
+		return key, node //passthru
+	}
+
+	pieces := []interface{}{fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\nMIME-Version: 1.0\n\n", cloudInitBoundary)}
+	for _, part := range parts {
+		pieces = append(pieces, fmt.Sprintf(
+			"--%s\nContent-Type: %s; charset=\"us-ascii\"\nMIME-Version: 1.0\nContent-Transfer-Encoding: 7bit\nContent-Disposition: attachment; filename=\"%s\"\n\n",
+			cloudInitBoundary, part.contentType, part.filename,
+		))
+
+		for _, piece := range contentPieces(part.content) {
+			if pieceString, ok := piece.(string); ok && strings.Contains(pieceString, cloudInitBoundary) {
+				panic(fmt.Errorf("Content of part '%s' contains the multipart boundary in Fn::CloudInitMultipart at '%s'", part.filename, formatPath(path)))
+			}
+			pieces = append(pieces, piece)
+		}
+		pieces = append(pieces, "\n")
+	}
+	pieces = append(pieces, fmt.Sprintf("--%s--\n", cloudInitBoundary))
+
+	document := joinPieces(pieces)
+	if !gzipped {
+		if base64ed {
+			return key, interface{}(map[string]interface{}{"Fn::Base64": document})
+		}
+
+		return key, document
+	}
+
+	var documentString string
+	if documentString, ok = document.(string); !ok {
+		return key, node //passthru (can't gzip content which depends upon unresolved values)
+	}
+
+	compressed := bytes.Buffer{}
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(documentString)); err != nil {
+		panic(fmt.Errorf("Error compressing Fn::CloudInitMultipart at '%s': %s", formatPath(path), err))
+	}
+
+	if err := writer.Close(); err != nil {
+		panic(fmt.Errorf("Error compressing Fn::CloudInitMultipart at '%s': %s", formatPath(path), err))
+	}
+
+	return key, interface{}(base64.StdEncoding.EncodeToString(compressed.Bytes()))
+}
@@ -0,0 +1,235 @@
+package rules
+
+import (
+	"bytes"
+	"compress/gzip"
+	"condense/template"
+	"deepstack"
+	"encoding/base64"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestFnCloudInitMultipart_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnCloudInitMultipart, "Fn::CloudInitMultipart", t)
+}
+
+func TestFnCloudInitMultipart_Passthru_BadArguments(t *testing.T) {
+	inputs := []interface{}{
+		"nonList",
+		[]interface{}{},
+		[]interface{}{"nonObject"},
+		[]interface{}{map[string]interface{}{"content": "x"}},
+		[]interface{}{map[string]interface{}{"contentType": "text/x-shellscript"}},
+		[]interface{}{map[string]interface{}{"contentType": "text/x-shellscript", "content": []interface{}{"x"}}},
+		[]interface{}{map[string]interface{}{"contentType": "text/x-shellscript", "content": "x", "other": "y"}},
+		map[string]interface{}{"Ref": "Parts"},
+		map[string]interface{}{"parts": []interface{}{map[string]interface{}{"contentType": "a", "content": "b"}}, "gzip": "yes"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::CloudInitMultipart": input,
+		})
+
+		newKey, newNode := FnCloudInitMultipart([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnCloudInitMultipart modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnCloudInitMultipart with bad arguments %v modified the data (%#v instead of %#v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnCloudInitMultipart_Passthru_GzipUnresolved(t *testing.T) {
+	input := interface{}(map[string]interface{}{
+		"Fn::CloudInitMultipart": map[string]interface{}{
+			"parts": []interface{}{
+				map[string]interface{}{"contentType": "text/x-shellscript", "content": map[string]interface{}{"Ref": "Script"}},
+			},
+			"gzip": true,
+		},
+	})
+
+	newKey, newNode := FnCloudInitMultipart([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnCloudInitMultipart modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, input) {
+		t.Fatalf("FnCloudInitMultipart gzipping unresolved content modified the data (%#v instead of %#v)", newNode, input)
+	}
+}
+
+func TestFnCloudInitMultipart_GzipInFor(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(MakeFnFor(&stack, &templateRules))
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnJoin)
+	templateRules.Attach(FnCloudInitMultipart)
+
+	input := interface{}(map[string]interface{}{
+		"Fn::For": []interface{}{
+			[]interface{}{"$n"},
+			[]interface{}{"a"},
+			map[string]interface{}{"Fn::CloudInitMultipart": map[string]interface{}{
+				"parts": []interface{}{
+					map[string]interface{}{"contentType": "text/x-shellscript", "content": map[string]interface{}{
+						"Fn::Join": []interface{}{"", []interface{}{"echo ", map[string]interface{}{"Ref": "$n"}}},
+					}},
+				},
+				"gzip": true,
+			}},
+		},
+	})
+
+	_, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+
+	var generated []interface{}
+	var ok bool
+	if generated, ok = newNode.([]interface{}); !ok || len(generated) != 1 {
+		t.Fatalf("FnCloudInitMultipart within Fn::For did not return the expected result (%#v)", newNode)
+	}
+
+	if _, ok = generated[0].(string); !ok {
+		t.Fatalf("FnCloudInitMultipart within Fn::For did not gzip the bound content (%#v)", generated[0])
+	}
+}
+
+const testCloudInitDocument = "Content-Type: multipart/mixed; boundary=\"==CONDENSE-MULTIPART-BOUNDARY==\"\n" +
+	"MIME-Version: 1.0\n" +
+	"\n" +
+	"--==CONDENSE-MULTIPART-BOUNDARY==\n" +
+	"Content-Type: text/cloud-config; charset=\"us-ascii\"\n" +
+	"MIME-Version: 1.0\n" +
+	"Content-Transfer-Encoding: 7bit\n" +
+	"Content-Disposition: attachment; filename=\"part-001\"\n" +
+	"\n" +
+	"#cloud-config\n" +
+	"\n" +
+	"--==CONDENSE-MULTIPART-BOUNDARY==\n" +
+	"Content-Type: text/x-shellscript; charset=\"us-ascii\"\n" +
+	"MIME-Version: 1.0\n" +
+	"Content-Transfer-Encoding: 7bit\n" +
+	"Content-Disposition: attachment; filename=\"setup.sh\"\n" +
+	"\n" +
+	"#!/bin/bash\n" +
+	"\n" +
+	"--==CONDENSE-MULTIPART-BOUNDARY==--\n"
+
+func TestFnCloudInitMultipart_Basic(t *testing.T) {
+	parts := []interface{}{
+		map[string]interface{}{"contentType": "text/cloud-config", "content": "#cloud-config\n"},
+		map[string]interface{}{"contentType": "text/x-shellscript", "content": "#!/bin/bash\n", "filename": "setup.sh"},
+	}
+
+	inputs := []interface{}{
+		parts,
+		map[string]interface{}{"parts": parts, "base64": true},
+	}
+
+	expected := []interface{}{
+		testCloudInitDocument,
+		map[string]interface{}{"Fn::Base64": testCloudInitDocument},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::CloudInitMultipart": input,
+		})
+
+		newKey, newNode := FnCloudInitMultipart([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnCloudInitMultipart modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnCloudInitMultipart of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnCloudInitMultipart_Unresolved(t *testing.T) {
+	input := interface{}(map[string]interface{}{
+		"Fn::CloudInitMultipart": []interface{}{
+			map[string]interface{}{
+				"contentType": "text/x-shellscript",
+				"content": map[string]interface{}{
+					"Fn::Join": []interface{}{"", []interface{}{"echo ", map[string]interface{}{"Ref": "AWS::Region"}}},
+				},
+			},
+			map[string]interface{}{
+				"contentType": "text/x-shellscript",
+				"content":     map[string]interface{}{"Ref": "Script"},
+			},
+		},
+	})
+
+	newKey, newNode := FnCloudInitMultipart([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnCloudInitMultipart modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	join, ok := singleKey(newNode, "Fn::Join")
+	if !ok {
+		t.Fatalf("FnCloudInitMultipart with unresolved content did not return an Fn::Join (%#v)", newNode)
+	}
+
+	pieces := join.([]interface{})[1].([]interface{})
+	expectedIntrinsics := []interface{}{
+		map[string]interface{}{"Ref": "AWS::Region"},
+		map[string]interface{}{"Ref": "Script"},
+	}
+
+	intrinsics := []interface{}{}
+	for i, piece := range pieces {
+		if _, isString := piece.(string); !isString {
+			intrinsics = append(intrinsics, piece)
+		} else if i > 0 {
+			if _, previousIsString := pieces[i-1].(string); previousIsString {
+				t.Fatalf("FnCloudInitMultipart did not merge adjacent strings (%#v)", pieces)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(intrinsics, expectedIntrinsics) {
+		t.Fatalf("FnCloudInitMultipart did not keep the unresolved intrinsics (%#v instead of %#v)", intrinsics, expectedIntrinsics)
+	}
+}
+
+func TestFnCloudInitMultipart_Gzip(t *testing.T) {
+	input := interface{}(map[string]interface{}{
+		"Fn::CloudInitMultipart": map[string]interface{}{
+			"parts": []interface{}{
+				map[string]interface{}{"contentType": "text/cloud-config", "content": "#cloud-config\n"},
+				map[string]interface{}{"contentType": "text/x-shellscript", "content": "#!/bin/bash\n", "filename": "setup.sh"},
+			},
+			"gzip": true,
+		},
+	})
+
+	_, newNode := FnCloudInitMultipart([]interface{}{"x", "y"}, input)
+	encoded, ok := newNode.(string)
+	if !ok {
+		t.Fatalf("FnCloudInitMultipart with gzip did not return a string (%#v)", newNode)
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("FnCloudInitMultipart with gzip did not return base64 (%s)", err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("FnCloudInitMultipart with gzip did not return gzipped data (%s)", err)
+	}
+
+	document, _ := ioutil.ReadAll(reader)
+	if string(document) != testCloudInitDocument {
+		t.Fatalf("FnCloudInitMultipart with gzip did not return the expected document (%q instead of %q)", document, testCloudInitDocument)
+	}
+}
@@ -0,0 +1,31 @@
+package rules
+
+func compareNumbers(path []interface{}, node interface{}, fnName string, compare func(a, b float64) bool) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := numericArgs(node, fnName)
+	if !ok || len(args) != 2 {
+		return key, node //passthru
+	}
+
+	return key, interface{}(compare(args[0], args[1]))
+}
+
+func FnLessThan(path []interface{}, node interface{}) (interface{}, interface{}) {
+	return compareNumbers(path, node, "Fn::LessThan", func(a, b float64) bool { return a < b })
+}
+
+func FnLessThanOrEqual(path []interface{}, node interface{}) (interface{}, interface{}) {
+	return compareNumbers(path, node, "Fn::LessThanOrEqual", func(a, b float64) bool { return a <= b })
+}
+
+func FnGreaterThan(path []interface{}, node interface{}) (interface{}, interface{}) {
+	return compareNumbers(path, node, "Fn::GreaterThan", func(a, b float64) bool { return a > b })
+}
+
+func FnGreaterThanOrEqual(path []interface{}, node interface{}) (interface{}, interface{}) {
+	return compareNumbers(path, node, "Fn::GreaterThanOrEqual", func(a, b float64) bool { return a >= b })
+}
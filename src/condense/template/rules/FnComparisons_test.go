@@ -0,0 +1,84 @@
+package rules
+
+import (
+	"condense/template"
+	"reflect"
+	"testing"
+)
+
+func TestFnComparisons_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnLessThan, "Fn::LessThan", t)
+	testRule_Passthru_NonMatching(FnLessThanOrEqual, "Fn::LessThanOrEqual", t)
+	testRule_Passthru_NonMatching(FnGreaterThan, "Fn::GreaterThan", t)
+	testRule_Passthru_NonMatching(FnGreaterThanOrEqual, "Fn::GreaterThanOrEqual", t)
+}
+
+func TestFnComparisons_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnLessThan, "Fn::LessThan", t)
+	testRule_Passthru_NonArgsList(FnLessThanOrEqual, "Fn::LessThanOrEqual", t)
+	testRule_Passthru_NonArgsList(FnGreaterThan, "Fn::GreaterThan", t)
+	testRule_Passthru_NonArgsList(FnGreaterThanOrEqual, "Fn::GreaterThanOrEqual", t)
+}
+
+func TestFnLessThan_Passthru_BadArguments(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{float64(1)},
+		[]interface{}{float64(1), float64(2), float64(3)},
+		[]interface{}{float64(1), "non-number"},
+		[]interface{}{map[string]interface{}{"Ref": "Unresolved"}, float64(1)},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::LessThan": input,
+		})
+
+		newKey, newNode := FnLessThan([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnLessThan modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnLessThan with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnComparisons_Basic(t *testing.T) {
+	rules := map[string]template.Rule{
+		"Fn::LessThan":           FnLessThan,
+		"Fn::LessThanOrEqual":    FnLessThanOrEqual,
+		"Fn::GreaterThan":        FnGreaterThan,
+		"Fn::GreaterThanOrEqual": FnGreaterThanOrEqual,
+	}
+
+	inputs := [][]interface{}{
+		[]interface{}{float64(1), float64(2)},
+		[]interface{}{float64(2), float64(2)},
+		[]interface{}{float64(3), float64(2)},
+	}
+
+	expected := map[string][]interface{}{
+		"Fn::LessThan":           []interface{}{true, false, false},
+		"Fn::LessThanOrEqual":    []interface{}{true, true, false},
+		"Fn::GreaterThan":        []interface{}{false, false, true},
+		"Fn::GreaterThanOrEqual": []interface{}{false, true, true},
+	}
+
+	for fnName, rule := range rules {
+		for i, input := range inputs {
+			input := interface{}(map[string]interface{}{
+				fnName: input,
+			})
+
+			newKey, newNode := rule([]interface{}{"x", "y"}, input)
+			if newKey != "y" {
+				t.Fatalf("%s modified the path (%v instead of %v)", fnName, newKey, "y")
+			}
+
+			if !reflect.DeepEqual(newNode, expected[fnName][i]) {
+				t.Fatalf("%s of %v did not return the expected result (%v instead of %v)", fnName, input, newNode, expected[fnName][i])
+			}
+		}
+	}
+}
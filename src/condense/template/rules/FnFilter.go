@@ -0,0 +1,68 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fmt"
+)
+
+func MakeFnFilter(sources *deepstack.DeepStack, templateRules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		raw, ok := singleKey(node, "Fn::Filter")
+		if !ok {
+			return key, node //passthru
+		}
+
+		args, ok := collectTemplateArgs(path, raw, 3, 1, templateRules)
+		if !ok {
+			return key, node //passthru
+		}
+
+		var refNames []interface{}
+		if refNames, ok = parseRefNames(args[0]); !ok {
+			return key, node //passthru
+		}
+
+		var values []interface{}
+		if values, ok = args[1].([]interface{}); !ok {
+			return key, node //passthru
+		}
+
+		predicate := interface{}(args[2])
+
+		filtered := []interface{}{}
+		for deepIndex, value := range values {
+			deepPath := make([]interface{}, len(path)+1)
+			copy(deepPath, path)
+			deepPath[cap(deepPath)-1] = interface{}(deepIndex)
+
+			sources.Push(bindRefNames(refNames, float64(deepIndex), value))
+			newIndex, processed := template.Walk(deepPath, predicate, templateRules)
+			sources.PopDiscard()
+
+			if skip, ok := newIndex.(bool); ok && skip {
+				continue
+			}
+
+			var keep bool
+			if keep, ok = processed.(bool); !ok {
+				if isUnresolved(processed) {
+					return key, node //passthru (predicate depends upon unbound values)
+				}
+
+				panic(fmt.Errorf("Fn::Filter predicate at '%s' did not resolve to a Boolean (got %#v)", formatPath(deepPath), processed))
+			}
+
+			if keep {
+				filtered = append(filtered, value)
+			}
+		}
+
+		return key, interface{}(filtered)
+	}
+}
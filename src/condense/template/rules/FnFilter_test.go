@@ -0,0 +1,172 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"reflect"
+	"testing"
+)
+
+func testMakeFnFilter(stack deepstack.DeepStack, rules template.Rules) template.Rule {
+	return MakeFnFilter(&stack, &rules)
+}
+
+func TestFnFilter_Passthru_NonMatching(t *testing.T) {
+	fnFilter := testMakeFnFilter(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonMatching(fnFilter, "Fn::Filter", t)
+}
+
+func TestFnFilter_Passthru_NonArgsList(t *testing.T) {
+	fnFilter := testMakeFnFilter(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonArgsList(fnFilter, "Fn::Filter", t)
+}
+
+func TestFnFilter_Passthru_BadArguments(t *testing.T) {
+	fnFilter := testMakeFnFilter(deepstack.DeepStack{}, template.Rules{})
+
+	inputs := [][]interface{}{
+		[]interface{}{"$value", []interface{}{1, 2}},
+		[]interface{}{"$value", []interface{}{1, 2}, true, "tooMany"},
+		[]interface{}{[]interface{}{"$i", "$value", "tooMany"}, []interface{}{1}, true},
+		[]interface{}{"$value", "nonList", true},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Filter": input,
+		})
+
+		newKey, newNode := fnFilter([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnFilter modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnFilter with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnFilter_Panic_NonBoolPredicate(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnFilter := testMakeFnFilter(deepstack.DeepStack{}, template.Rules{})
+	input := interface{}(map[string]interface{}{
+		"Fn::Filter": []interface{}{"$value", []interface{}{"a"}, "nonBool"},
+	})
+
+	_, _ = fnFilter([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnFilter with a non-boolean predicate did not panic")
+}
+
+func TestFnFilter_Basic(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeFnGetAtt(&stack, &templateRules))
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnEquals)
+	templateRules.Attach(FnNot)
+
+	fnFilter := MakeFnFilter(&stack, &templateRules)
+
+	subnets := []interface{}{
+		map[string]interface{}{"name": "a", "public": true},
+		map[string]interface{}{"name": "b", "public": false},
+		map[string]interface{}{"name": "c", "public": true},
+	}
+
+	inputs := []interface{}{
+		[]interface{}{"$subnet", subnets, map[string]interface{}{"Fn::GetAtt": []interface{}{"$subnet", "public"}}},
+		[]interface{}{
+			[]interface{}{"$i", "$subnet"},
+			subnets,
+			map[string]interface{}{"Fn::Not": map[string]interface{}{
+				"Fn::Equals": []interface{}{map[string]interface{}{"Ref": "$i"}, float64(0)},
+			}},
+		},
+	}
+
+	expected := []interface{}{
+		[]interface{}{subnets[0], subnets[2]},
+		[]interface{}{subnets[1], subnets[2]},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Filter": input,
+		})
+
+		newKey, newNode := fnFilter([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnFilter modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnFilter of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnFilter_NestedOuterBinding(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(MakeFnFor(&stack, &templateRules))
+	templateRules.AttachEarly(MakeFnFilter(&stack, &templateRules))
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnGreaterThan)
+
+	input := interface{}(map[string]interface{}{
+		"Fn::For": []interface{}{
+			"$min",
+			[]interface{}{float64(1), float64(2)},
+			map[string]interface{}{
+				"Fn::Filter": []interface{}{
+					"$v",
+					[]interface{}{float64(1), float64(2), float64(3)},
+					map[string]interface{}{"Fn::GreaterThan": []interface{}{
+						map[string]interface{}{"Ref": "$v"},
+						map[string]interface{}{"Ref": "$min"},
+					}},
+				},
+			},
+		},
+	})
+
+	expected := interface{}([]interface{}{
+		[]interface{}{float64(2), float64(3)},
+		[]interface{}{float64(3)},
+	})
+
+	newKey, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if newKey != "y" {
+		t.Fatalf("FnFilter modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnFilter within Fn::For did not return the expected result (%#v instead of %#v)", newNode, expected)
+	}
+}
+
+func TestFnFilter_Passthru_UnresolvedPredicate(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+
+	fnFilter := MakeFnFilter(&stack, &templateRules)
+	input := interface{}(map[string]interface{}{
+		"Fn::Filter": []interface{}{"$value", []interface{}{"a"}, map[string]interface{}{"Ref": "Unbound"}},
+	})
+
+	newKey, newNode := fnFilter([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnFilter modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, input) {
+		t.Fatalf("FnFilter with an unresolved predicate modified the data (%#v instead of %#v)", newNode, input)
+	}
+}
@@ -12,7 +12,7 @@ type Stater interface {
 	Stat(path string) (os.FileInfo, error)
 }
 
-func MakeFnFindFile(stater Stater) template.Rule {
+func MakeFnFindFile(stater Stater, includes *Includes) template.Rule {
 	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
 		key := interface{}(nil)
 		if len(path) > 0 {
@@ -54,6 +54,13 @@ func MakeFnFindFile(stater Stater) template.Rule {
 
 		for _, prefix := range prefixStrings {
 			fullpath := fspath.Join(prefix, tail)
+			if !includes.RelativeToCwd {
+				var err error
+				if fullpath, err = includes.Resolve(fullpath); err != nil {
+					panic(fmt.Errorf("Unable to locate file '%s': %s", tail, err))
+				}
+			}
+
 			if _, err := stater.Stat(fullpath); err == nil {
 				return key, interface{}(fullpath)
 			}
@@ -11,7 +11,7 @@ import (
 
 func testMakeFnFindFile(files map[string]string) template.Rule {
 	fs := mapfs.New(files)
-	return MakeFnFindFile(fs)
+	return MakeFnFindFile(fs, NewIncludes("/", 100))
 }
 
 func TestFnFindFile_Passthru_NonMatching(t *testing.T) {
@@ -127,3 +127,25 @@ func TestFnFindFile_Basic(t *testing.T) {
 		t.Fatalf("FnFindFile did not return the expected result (%#v instead of %#v)", newNode, expected)
 	}
 }
+
+func TestFnFindFile_RelativePrefixes(t *testing.T) {
+	fs := mapfs.New(map[string]string{
+		"project/b/theFile": "contents",
+		"b/theFile":         "contents",
+	})
+	fnFindFile := MakeFnFindFile(fs, NewIncludes("/project", 100))
+
+	input := interface{}(map[string]interface{}{
+		"Fn::FindFile": []interface{}{[]interface{}{"a", "b"}, "theFile"},
+	})
+
+	expected := interface{}("/project/b/theFile")
+	newKey, newNode := fnFindFile([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnFindFile modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnFindFile did not resolve relative prefixes (%#v instead of %#v)", newNode, expected)
+	}
+}
@@ -6,6 +6,45 @@ import (
 	"fallbackmap"
 )
 
+// parseRefNames interprets the binding names of Fn::For and similar
+// rules: either a single name for the value, or a list of [index, value]
+// names, where either may be null to leave it unbound.
+func parseRefNames(arg interface{}) ([]interface{}, bool) {
+	var refNames []interface{}
+	var ok bool
+
+	if refNames, ok = arg.([]interface{}); ok {
+		if len(refNames) == 1 {
+			refNames = []interface{}{nil, refNames[0]}
+		} else if len(refNames) != 2 {
+			return nil, false
+		}
+	} else {
+		refNames = []interface{}{nil, arg}
+	}
+
+	for _, refName := range refNames {
+		if _, ok = refName.(string); !ok && refName != nil {
+			return nil, false
+		}
+	}
+
+	return refNames, true
+}
+
+func bindRefNames(refNames []interface{}, index interface{}, value interface{}) fallbackmap.DeepMap {
+	refMap := make(map[string]interface{})
+	if refNames[0] != nil {
+		refMap[refNames[0].(string)] = index
+	}
+
+	if refNames[1] != nil {
+		refMap[refNames[1].(string)] = value
+	}
+
+	return fallbackmap.DeepMap(refMap)
+}
+
 func MakeFnFor(sources *deepstack.DeepStack, templateRules *template.Rules) template.Rule {
 	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
 		key := interface{}(nil)
@@ -18,44 +57,14 @@ func MakeFnFor(sources *deepstack.DeepStack, templateRules *template.Rules) temp
 			return key, node //passthru
 		}
 
-		args, ok := collectArgs(
-			raw,
-			func(argsSoFar []interface{}) bool { return len(argsSoFar) < 3 },
-			func(argsSoFar []interface{}, arg interface{}) (skip bool, newNode interface{}) {
-				// unconditionally process the argument, in case it needs to be skipped
-				key, node := template.Walk(path, arg, templateRules)
-				if skip, ok := key.(bool); ok && skip {
-					return true, nil
-				}
-
-				if len(argsSoFar) == 2 {
-					return false, arg // return unprocessed 3rd arg. It's a template.
-				}
-
-				return false, node
-			},
-		)
+		args, ok := collectTemplateArgs(path, raw, 3, 1, templateRules)
 		if !ok {
 			return key, node //passthru
 		}
 
 		var refNames []interface{}
-		var refName interface{}
-
-		if refNames, ok = args[0].([]interface{}); ok {
-			if len(refNames) == 1 {
-				refNames = []interface{}{nil, refNames[0]}
-			} else if len(refNames) != 2 {
-				return key, node //passthru
-			}
-		} else {
-			refNames = []interface{}{nil, args[0]}
-		}
-
-		for _, refName = range refNames {
-			if _, ok = refName.(string); !ok && refName != nil {
-				return key, node //passthru
-			}
+		if refNames, ok = parseRefNames(args[0]); !ok {
+			return key, node //passthru
 		}
 
 		valuesInterface := args[1]
@@ -69,20 +78,11 @@ func MakeFnFor(sources *deepstack.DeepStack, templateRules *template.Rules) temp
 
 		generated := []interface{}{}
 		for deepIndex, value := range values {
-			refMap := make(map[string]interface{})
-			if refNames[0] != nil {
-				refMap[refNames[0].(string)] = float64(deepIndex)
-			}
-
-			if refNames[1] != nil {
-				refMap[refNames[1].(string)] = value
-			}
-
 			deepPath := make([]interface{}, len(path)+1)
 			copy(deepPath, path)
 			deepPath[cap(deepPath)-1] = interface{}(deepIndex)
 
-			sources.Push(fallbackmap.DeepMap(refMap))
+			sources.Push(bindRefNames(refNames, float64(deepIndex), value))
 
 			newIndex, processed := template.Walk(deepPath, loopTemplate, templateRules)
 			sources.PopDiscard()
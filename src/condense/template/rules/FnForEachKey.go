@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fmt"
+	"sort"
+)
+
+func MakeFnForEachKey(sources *deepstack.DeepStack, templateRules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		raw, ok := singleKey(node, "Fn::ForEachKey")
+		if !ok {
+			return key, node //passthru
+		}
+
+		args, ok := collectTemplateArgsBetween(path, raw, 3, 4, 2, 4, templateRules)
+		if !ok {
+			return key, node //passthru
+		}
+
+		// with a key template, an object is generated rather than an array
+		templateCount := len(args) - 2
+
+		var refNames []interface{}
+		if refNames, ok = parseRefNames(args[0]); !ok {
+			return key, node //passthru
+		}
+
+		var values map[string]interface{}
+		if values, ok = args[1].(map[string]interface{}); !ok {
+			return key, node //passthru
+		}
+
+		keys := []string{}
+		for deepKey := range values {
+			keys = append(keys, deepKey)
+		}
+		sort.Strings(keys)
+
+		generatedArray := []interface{}{}
+		generatedMap := make(map[string]interface{})
+		for _, deepKey := range keys {
+			deepPath := make([]interface{}, len(path)+1)
+			copy(deepPath, path)
+			deepPath[cap(deepPath)-1] = interface{}(deepKey)
+
+			sources.Push(bindRefNames(refNames, deepKey, values[deepKey]))
+
+			newKey, processed := template.Walk(deepPath, args[len(args)-1], templateRules)
+			if skip, ok := newKey.(bool); ok && skip {
+				sources.PopDiscard()
+				continue
+			}
+
+			if templateCount == 1 {
+				sources.PopDiscard()
+				generatedArray = append(generatedArray, processed)
+				continue
+			}
+
+			_, processedKey := template.Walk(deepPath, args[2], templateRules)
+			sources.PopDiscard()
+
+			var processedKeyString string
+			if processedKeyString, ok = processedKey.(string); !ok {
+				if isUnresolved(processedKey) {
+					return key, node //passthru (key depends upon unbound values)
+				}
+
+				panic(fmt.Errorf("Fn::ForEachKey key template at '%s' did not resolve to a string (got %#v)", formatPath(deepPath), processedKey))
+			}
+
+			generatedMap[processedKeyString] = processed
+		}
+
+		if templateCount == 1 {
+			return key, interface{}(generatedArray)
+		}
+
+		return key, interface{}(generatedMap)
+	}
+}
@@ -0,0 +1,171 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"reflect"
+	"testing"
+)
+
+func testMakeFnForEachKey(stack deepstack.DeepStack, rules template.Rules) template.Rule {
+	return MakeFnForEachKey(&stack, &rules)
+}
+
+func TestFnForEachKey_Passthru_NonMatching(t *testing.T) {
+	fnForEachKey := testMakeFnForEachKey(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonMatching(fnForEachKey, "Fn::ForEachKey", t)
+}
+
+func TestFnForEachKey_Passthru_NonArgsList(t *testing.T) {
+	fnForEachKey := testMakeFnForEachKey(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonArgsList(fnForEachKey, "Fn::ForEachKey", t)
+}
+
+func TestFnForEachKey_Passthru_BadArguments(t *testing.T) {
+	fnForEachKey := testMakeFnForEachKey(deepstack.DeepStack{}, template.Rules{})
+
+	inputs := [][]interface{}{
+		[]interface{}{"$value", map[string]interface{}{"a": 1}},
+		[]interface{}{"$value", map[string]interface{}{"a": 1}, "aKey", "aTemplate", "tooMany"},
+		[]interface{}{[]interface{}{"$key", 1}, map[string]interface{}{"a": 1}, "aTemplate"},
+		[]interface{}{"$value", []interface{}{1, 2}, "aTemplate"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::ForEachKey": input,
+		})
+
+		newKey, newNode := fnForEachKey([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnForEachKey modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnForEachKey with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnForEachKey_Panic_NonStringKey(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnForEachKey := testMakeFnForEachKey(deepstack.DeepStack{}, template.Rules{})
+	input := interface{}(map[string]interface{}{
+		"Fn::ForEachKey": []interface{}{"$value", map[string]interface{}{"a": 1}, float64(1), "aTemplate"},
+	})
+
+	_, _ = fnForEachKey([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnForEachKey with a non-string key template did not panic")
+}
+
+func TestFnForEachKey_Basic(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnJoin)
+
+	fnForEachKey := MakeFnForEachKey(&stack, &templateRules)
+
+	queues := map[string]interface{}{
+		"c": "three",
+		"a": "one",
+		"b": "two",
+	}
+
+	inputs := []interface{}{
+		[]interface{}{
+			[]interface{}{"$key", "$value"},
+			queues,
+			[]interface{}{map[string]interface{}{"Ref": "$key"}, map[string]interface{}{"Ref": "$value"}},
+		},
+		[]interface{}{
+			[]interface{}{"$key", "$value"},
+			queues,
+			map[string]interface{}{"Fn::Join": []interface{}{"", []interface{}{"Queue", map[string]interface{}{"Ref": "$key"}}}},
+			map[string]interface{}{"QueueName": map[string]interface{}{"Ref": "$value"}},
+		},
+	}
+
+	expected := []interface{}{
+		[]interface{}{
+			[]interface{}{"a", "one"},
+			[]interface{}{"b", "two"},
+			[]interface{}{"c", "three"},
+		},
+		map[string]interface{}{
+			"Queuea": map[string]interface{}{"QueueName": "one"},
+			"Queueb": map[string]interface{}{"QueueName": "two"},
+			"Queuec": map[string]interface{}{"QueueName": "three"},
+		},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::ForEachKey": input,
+		})
+
+		newKey, newNode := fnForEachKey([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnForEachKey modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnForEachKey of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnForEachKey_NestedOuterBinding(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(ExcludeComments)
+	templateRules.AttachEarly(MakeFnForEachKey(&stack, &templateRules))
+	templateRules.AttachEarly(MakeFnWith(&stack, &templateRules))
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnJoin)
+
+	input := interface{}(map[string]interface{}{
+		"Fn::With": []interface{}{
+			map[string]interface{}{"$prefix": "Queue"},
+			map[string]interface{}{
+				"Fn::ForEachKey": []interface{}{
+					[]interface{}{"$key", "$value"},
+					map[string]interface{}{"a": float64(1), "b": float64(2)},
+					map[string]interface{}{"$comment": "skipped, so there is no key template"},
+					map[string]interface{}{"Fn::Join": []interface{}{"", []interface{}{
+						map[string]interface{}{"Ref": "$prefix"},
+						map[string]interface{}{"Ref": "$key"},
+					}}},
+				},
+			},
+		},
+	})
+
+	expected := interface{}([]interface{}{"Queuea", "Queueb"})
+	newKey, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if newKey != "y" {
+		t.Fatalf("FnForEachKey modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnForEachKey within Fn::With did not return the expected result (%#v instead of %#v)", newNode, expected)
+	}
+
+	input.(map[string]interface{})["Fn::With"].([]interface{})[1].(map[string]interface{})["Fn::ForEachKey"].([]interface{})[2] = map[string]interface{}{
+		"Fn::Join": []interface{}{"", []interface{}{
+			map[string]interface{}{"Ref": "$prefix"},
+			map[string]interface{}{"Ref": "$key"},
+		}},
+	}
+
+	expected = interface{}(map[string]interface{}{"Queuea": "Queuea", "Queueb": "Queueb"})
+	_, newNode = template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnForEachKey within Fn::With did not return the expected object (%#v instead of %#v)", newNode, expected)
+	}
+}
@@ -0,0 +1,67 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fmt"
+)
+
+func MakeFnGroupBy(sources *deepstack.DeepStack, templateRules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		raw, ok := singleKey(node, "Fn::GroupBy")
+		if !ok {
+			return key, node //passthru
+		}
+
+		args, ok := collectTemplateArgs(path, raw, 3, 1, templateRules)
+		if !ok {
+			return key, node //passthru
+		}
+
+		var refNames []interface{}
+		if refNames, ok = parseRefNames(args[0]); !ok {
+			return key, node //passthru
+		}
+
+		var values []interface{}
+		if values, ok = args[1].([]interface{}); !ok {
+			return key, node //passthru
+		}
+
+		keyTemplate := interface{}(args[2])
+
+		grouped := make(map[string]interface{})
+		for deepIndex, value := range values {
+			deepPath := make([]interface{}, len(path)+1)
+			copy(deepPath, path)
+			deepPath[cap(deepPath)-1] = interface{}(deepIndex)
+
+			sources.Push(bindRefNames(refNames, float64(deepIndex), value))
+			newIndex, groupKey := template.Walk(deepPath, keyTemplate, templateRules)
+			sources.PopDiscard()
+
+			if skip, ok := newIndex.(bool); ok && skip {
+				continue
+			}
+
+			var groupKeyString string
+			if groupKeyString, ok = groupKey.(string); !ok {
+				if isUnresolved(groupKey) {
+					return key, node //passthru (key depends upon unbound values)
+				}
+
+				panic(fmt.Errorf("Fn::GroupBy key template at '%s' did not resolve to a string (got %#v)", formatPath(deepPath), groupKey))
+			}
+
+			group, _ := grouped[groupKeyString].([]interface{})
+			grouped[groupKeyString] = append(group, value)
+		}
+
+		return key, interface{}(grouped)
+	}
+}
@@ -0,0 +1,142 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"reflect"
+	"testing"
+)
+
+func testMakeFnGroupBy(stack deepstack.DeepStack, rules template.Rules) template.Rule {
+	return MakeFnGroupBy(&stack, &rules)
+}
+
+func TestFnGroupBy_Passthru_NonMatching(t *testing.T) {
+	fnGroupBy := testMakeFnGroupBy(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonMatching(fnGroupBy, "Fn::GroupBy", t)
+}
+
+func TestFnGroupBy_Passthru_NonArgsList(t *testing.T) {
+	fnGroupBy := testMakeFnGroupBy(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonArgsList(fnGroupBy, "Fn::GroupBy", t)
+}
+
+func TestFnGroupBy_Passthru_BadArguments(t *testing.T) {
+	fnGroupBy := testMakeFnGroupBy(deepstack.DeepStack{}, template.Rules{})
+
+	inputs := [][]interface{}{
+		[]interface{}{"$value", []interface{}{1, 2}},
+		[]interface{}{"$value", []interface{}{1, 2}, "aTemplate", "tooMany"},
+		[]interface{}{"$value", "nonList", "aTemplate"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::GroupBy": input,
+		})
+
+		newKey, newNode := fnGroupBy([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnGroupBy modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnGroupBy with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnGroupBy_Panic_NonStringKey(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnGroupBy := testMakeFnGroupBy(deepstack.DeepStack{}, template.Rules{})
+	input := interface{}(map[string]interface{}{
+		"Fn::GroupBy": []interface{}{"$value", []interface{}{"a"}, float64(1)},
+	})
+
+	_, _ = fnGroupBy([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnGroupBy with a non-string key template did not panic")
+}
+
+func TestFnGroupBy_Basic(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeFnGetAtt(&stack, &templateRules))
+
+	fnGroupBy := MakeFnGroupBy(&stack, &templateRules)
+
+	services := []interface{}{
+		map[string]interface{}{"name": "a", "tier": "web"},
+		map[string]interface{}{"name": "b", "tier": "worker"},
+		map[string]interface{}{"name": "c", "tier": "web"},
+	}
+
+	input := interface{}(map[string]interface{}{
+		"Fn::GroupBy": []interface{}{
+			"$service",
+			services,
+			map[string]interface{}{"Fn::GetAtt": []interface{}{"$service", "tier"}},
+		},
+	})
+
+	expected := map[string]interface{}{
+		"web":    []interface{}{services[0], services[2]},
+		"worker": []interface{}{services[1]},
+	}
+
+	newKey, newNode := fnGroupBy([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnGroupBy modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnGroupBy of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected)
+	}
+}
+
+func TestFnGroupBy_NestedOuterBinding(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(MakeFnGroupBy(&stack, &templateRules))
+	templateRules.AttachEarly(MakeFnLet(&stack, &templateRules))
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(MakeFnGetAtt(&stack, &templateRules))
+	templateRules.Attach(FnJoin)
+
+	input := interface{}(map[string]interface{}{
+		"Fn::Let": []interface{}{
+			[]interface{}{[]interface{}{"$prefix", "tier-"}},
+			map[string]interface{}{
+				"Fn::GroupBy": []interface{}{
+					"$service",
+					[]interface{}{
+						map[string]interface{}{"name": "a", "tier": "web"},
+						map[string]interface{}{"name": "b", "tier": "db"},
+					},
+					map[string]interface{}{"Fn::Join": []interface{}{"", []interface{}{
+						map[string]interface{}{"Ref": "$prefix"},
+						map[string]interface{}{"Fn::GetAtt": []interface{}{"$service", "tier"}},
+					}}},
+				},
+			},
+		},
+	})
+
+	expected := interface{}(map[string]interface{}{
+		"tier-web": []interface{}{map[string]interface{}{"name": "a", "tier": "web"}},
+		"tier-db":  []interface{}{map[string]interface{}{"name": "b", "tier": "db"}},
+	})
+
+	newKey, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if newKey != "y" {
+		t.Fatalf("FnGroupBy modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnGroupBy within Fn::Let did not return the expected result (%#v instead of %#v)", newNode, expected)
+	}
+}
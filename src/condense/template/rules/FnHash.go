@@ -0,0 +1,143 @@
+package rules
+
+import (
+	"condense/template"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"golang.org/x/tools/godoc/vfs"
+	"hash"
+	"io"
+)
+
+// hashArgs splits the arguments of a hashing function, which are always a
+// list of [value, algorithm, encoding], into the value to be hashed, and
+// the (optional) algorithm and encoding.
+func hashArgs(argsInterface interface{}) (value interface{}, algorithm string, encoding string, ok bool) {
+	algorithm, encoding = "sha256", "hex"
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return nil, "", "", false
+	}
+
+	if len(args) < 1 || len(args) > 3 {
+		return nil, "", "", false
+	}
+
+	if len(args) > 1 {
+		if algorithm, ok = args[1].(string); !ok {
+			return nil, "", "", false
+		}
+	}
+
+	if len(args) > 2 {
+		if encoding, ok = args[2].(string); !ok {
+			return nil, "", "", false
+		}
+	}
+
+	return args[0], algorithm, encoding, true
+}
+
+func newHash(path []interface{}, fnName string, algorithm string) hash.Hash {
+	switch algorithm {
+	case "sha256":
+		return sha256.New()
+	case "sha1":
+		return sha1.New()
+	case "md5":
+		return md5.New()
+	}
+
+	panic(fmt.Errorf("Unknown hash algorithm '%s' in %s at '%s'", algorithm, fnName, formatPath(path)))
+}
+
+func encodeHash(path []interface{}, fnName string, encoding string, sum []byte) string {
+	switch encoding {
+	case "hex":
+		return hex.EncodeToString(sum)
+	case "base64":
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+
+	panic(fmt.Errorf("Unknown hash encoding '%s' in %s at '%s'", encoding, fnName, formatPath(path)))
+}
+
+func FnHash(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Hash")
+	if !ok {
+		return key, node //passthru
+	}
+
+	value, algorithm, encoding, ok := hashArgs(argsInterface)
+	if !ok {
+		return key, node //passthru
+	}
+
+	if isUnresolved(value) {
+		return key, node //can't hash unresolved intrinsics, passthru
+	}
+
+	pieces, _ := jsonPieces(path, value)
+	canonical := joinPieces(pieces).(string)
+
+	hasher := newHash(path, "Fn::Hash", algorithm)
+	io.WriteString(hasher, canonical)
+	return key, interface{}(encodeHash(path, "Fn::Hash", encoding, hasher.Sum(nil)))
+}
+
+func MakeFnFileHash(opener vfs.Opener, includes *Includes) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		argsInterface, ok := singleKey(node, "Fn::FileHash")
+		if !ok {
+			return key, node //passthru
+		}
+
+		if filename, isString := argsInterface.(string); isString {
+			argsInterface = []interface{}{filename}
+		}
+
+		value, algorithm, encoding, ok := hashArgs(argsInterface)
+		if !ok {
+			return key, node //passthru
+		}
+
+		var argString string
+		if argString, ok = value.(string); !ok {
+			return key, node //passthru
+		}
+
+		var absPath string
+		var err error
+		if absPath, err = includes.Resolve(argString); err != nil {
+			panic(fmt.Errorf("Error opening hashed file '%s': %s", argString, err))
+		}
+
+		var dataStream io.ReadCloser
+		if dataStream, err = opener.Open(absPath); err != nil {
+			panic(fmt.Errorf("Error opening hashed file '%s': %s", absPath, err))
+		}
+		defer dataStream.Close()
+
+		hasher := newHash(path, "Fn::FileHash", algorithm)
+		if _, err = io.Copy(hasher, dataStream); err != nil {
+			panic(fmt.Errorf("Error loading hashed file '%s': %s", argString, err))
+		}
+
+		return key, interface{}(encodeHash(path, "Fn::FileHash", encoding, hasher.Sum(nil)))
+	}
+}
@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"condense/template"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/godoc/vfs/mapfs"
+)
+
+func TestFnHash_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnHash, "Fn::Hash", t)
+}
+
+func TestFnHash_Passthru_BadArguments(t *testing.T) {
+	inputs := []interface{}{
+		[]interface{}{},
+		[]interface{}{"value", "sha256", "hex", "tooMany"},
+		[]interface{}{"value", float64(1)},
+		"value",
+		map[string]interface{}{"a": "one"},
+		[]interface{}{map[string]interface{}{"Ref": "Unresolved"}},
+		[]interface{}{map[string]interface{}{"a": map[string]interface{}{"Ref": "Unresolved"}}},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Hash": input,
+		})
+
+		newKey, newNode := FnHash([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnHash modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnHash with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnHash_Panic_UnknownAlgorithm(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::Hash": []interface{}{"value", "crc32"},
+	})
+
+	_, _ = FnHash([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnHash with an unknown algorithm did not panic")
+}
+
+func TestFnHash_Basic(t *testing.T) {
+	inputs := []interface{}{
+		[]interface{}{map[string]interface{}{"b": []interface{}{float64(1), float64(2)}, "a": "one"}},
+		[]interface{}{map[string]interface{}{"b": []interface{}{float64(1), 2}, "a": "one"}},
+		[]interface{}{"abc", "md5"},
+		[]interface{}{[]interface{}{float64(1), float64(2)}},
+	}
+
+	expected := []interface{}{
+		"9043a9ddba493444bdc01902fe8fb24f8b76d2b7af1e01113a186704222b0ab3",
+		"9043a9ddba493444bdc01902fe8fb24f8b76d2b7af1e01113a186704222b0ab3",
+		"ebd9f4c7b06cb0aaf5d13d80e49d8b90",
+		"49a64717d5d4cb19952e6eac2946415cf6879adacf9908e7d872332d32c6e684",
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Hash": input,
+		})
+
+		newKey, newNode := FnHash([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnHash modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnHash of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func testMakeFnFileHash(files map[string]string) template.Rule {
+	fs := mapfs.New(files)
+	return MakeFnFileHash(fs, NewIncludes("/", 100))
+}
+
+func TestFnFileHash_Passthru_NonMatching(t *testing.T) {
+	fnFileHash := testMakeFnFileHash(map[string]string{})
+	testRule_Passthru_NonMatching(fnFileHash, "Fn::FileHash", t)
+}
+
+func TestFnFileHash_Passthru_NonStringArgument(t *testing.T) {
+	fnFileHash := testMakeFnFileHash(map[string]string{})
+
+	input := interface{}(map[string]interface{}{
+		"Fn::FileHash": float64(1),
+	})
+
+	newKey, newNode := fnFileHash([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnFileHash modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, input) {
+		t.Fatalf("FnFileHash with non-string argument modified the data (%#v instead of %#v)", newNode, input)
+	}
+}
+
+func TestFnFileHash_Panic_BadFilename(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnFileHash := testMakeFnFileHash(map[string]string{"a": "content"})
+
+	input := interface{}(map[string]interface{}{
+		"Fn::FileHash": "/b",
+	})
+
+	_, _ = fnFileHash([]interface{}{"x", "y"}, input)
+	t.Fatalf("Hashing a non-existant file did not panic")
+}
+
+func TestFnFileHash_Basic(t *testing.T) {
+	fnFileHash := testMakeFnFileHash(map[string]string{"index.py": "print(1)\n"})
+
+	inputs := []interface{}{
+		"/index.py",
+		[]interface{}{"/index.py", "sha1", "base64"},
+	}
+
+	expected := []interface{}{
+		"cc42155088fca5730758db72b2a5bca33112a941dfaa2d43098ec422ce4ea213",
+		"6biwa8QVrVs/PMVrU1M62E+g6Sc=",
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::FileHash": input,
+		})
+
+		newKey, newNode := fnFileHash([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnFileHash modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnFileHash of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
@@ -2,14 +2,134 @@ package rules
 
 import (
 	"condense/template"
-	"encoding/json"
 	"fmt"
 	"golang.org/x/tools/godoc/vfs"
 	"io"
-	"path/filepath"
+	"strconv"
+	"strings"
 )
 
-func MakeFnIncludeFile(opener vfs.Opener, rules *template.Rules) template.Rule {
+// splitFragment separates a JSON Pointer fragment from a filename, in the
+// form "filename#/pointer".
+func splitFragment(filename string) (string, string) {
+	i := strings.Index(filename, "#")
+	if i < 0 {
+		return filename, ""
+	}
+
+	pointer := filename[i+1:]
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return filename, ""
+	}
+
+	return filename[:i], pointer
+}
+
+// includeFileArgs accepts either a filename (optionally with a "#/pointer"
+// fragment), a list of [filename, pointer], or an object of the form
+// {"path": filename, "format": format, "pointer": pointer}, where format
+// and pointer are optional.
+func includeFileArgs(argInterface interface{}) (filename string, format string, pointer string, ok bool) {
+	if filename, ok = argInterface.(string); ok {
+		filename, pointer = splitFragment(filename)
+		return filename, includeFormat(filename), pointer, true
+	}
+
+	if args, ok := argInterface.([]interface{}); ok {
+		if len(args) != 2 {
+			return "", "", "", false
+		}
+
+		if filename, ok = args[0].(string); !ok {
+			return "", "", "", false
+		}
+
+		if pointer, ok = args[1].(string); !ok {
+			return "", "", "", false
+		}
+
+		return filename, includeFormat(filename), pointer, true
+	}
+
+	var args map[string]interface{}
+	if args, ok = argInterface.(map[string]interface{}); !ok {
+		return "", "", "", false
+	}
+
+	for argKey := range args {
+		if argKey != "path" && argKey != "format" && argKey != "pointer" {
+			return "", "", "", false
+		}
+	}
+
+	if filename, ok = args["path"].(string); !ok {
+		return "", "", "", false
+	}
+
+	format = includeFormat(filename)
+	if formatInterface, hasFormat := args["format"]; hasFormat {
+		if format, ok = formatInterface.(string); !ok {
+			return "", "", "", false
+		}
+	}
+
+	if pointerInterface, hasPointer := args["pointer"]; hasPointer {
+		if pointer, ok = pointerInterface.(string); !ok {
+			return "", "", "", false
+		}
+	}
+
+	return filename, format, pointer, true
+}
+
+// resolvePointer finds the value within document referenced by an RFC 6901
+// JSON Pointer.
+func resolvePointer(document interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return document, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON Pointer '%s' does not begin with '/'", pointer)
+	}
+
+	current := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+
+		switch typed := current.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if current, ok = typed[token]; !ok {
+				return nil, fmt.Errorf("No key '%s' found for JSON Pointer '%s'", token, pointer)
+			}
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+				return nil, fmt.Errorf("Invalid array index '%s' in JSON Pointer '%s'", token, pointer)
+			}
+
+			if index >= len(typed) {
+				return nil, fmt.Errorf("Array index '%s' out of range for JSON Pointer '%s'", token, pointer)
+			}
+
+			current = typed[index]
+		default:
+			return nil, fmt.Errorf("Cannot find '%s' within a scalar for JSON Pointer '%s'", token, pointer)
+		}
+	}
+
+	return current, nil
+}
+
+type includeCacheKey struct {
+	absPath string
+	format  string
+}
+
+func MakeFnIncludeFile(opener vfs.Opener, includes *Includes, rules *template.Rules) template.Rule {
+	cache := map[includeCacheKey]interface{}{}
+
 	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
 		key := interface{}(nil)
 		if len(path) > 0 {
@@ -21,29 +141,48 @@ func MakeFnIncludeFile(opener vfs.Opener, rules *template.Rules) template.Rule {
 			return key, node //passthru
 		}
 
-		var argString string
-		if argString, ok = argInterface.(string); !ok {
+		var argString, format, pointer string
+		if argString, format, pointer, ok = includeFileArgs(argInterface); !ok {
 			return key, node //passthru
 		}
 
+		var decoder includeDecoder
+		if decoder, ok = includeDecoders[format]; !ok {
+			panic(fmt.Errorf("Unknown format '%s' for imported file '%s'", format, argString))
+		}
+
 		var absPath string
 		var err error
-		if absPath, err = filepath.Abs(argString); err != nil {
+		if absPath, err = includes.Resolve(argString); err != nil {
 			panic(fmt.Errorf("Error opening imported file '%s': %s", argString, err))
 		}
 
-		var jsonStream io.Reader
-		if jsonStream, err = opener.Open(absPath); err != nil {
-			panic(fmt.Errorf("Error opening imported file '%s': %s", absPath, err))
+		cacheKey := includeCacheKey{absPath: absPath, format: format}
+		var includedTemplate interface{}
+		if includedTemplate, ok = cache[cacheKey]; !ok {
+			var stream io.Reader
+			if stream, err = opener.Open(absPath); err != nil {
+				panic(fmt.Errorf("Error opening imported file '%s': %s", absPath, err))
+			}
+
+			if includedTemplate, err = decoder(stream); err != nil {
+				panic(fmt.Errorf("Error loading imported file '%s': %s", argString, err))
+			}
+
+			cache[cacheKey] = includedTemplate
 		}
 
-		dec := json.NewDecoder(jsonStream)
-		includedTemplate := interface{}(nil)
-		if err := dec.Decode(&includedTemplate); err != nil {
+		if includedTemplate, err = resolvePointer(includedTemplate, pointer); err != nil {
 			panic(fmt.Errorf("Error loading imported file '%s': %s", argString, err))
 		}
 
+		if err = includes.Enter(absPath, pointer); err != nil {
+			panic(fmt.Errorf("Error including file at '%s': %s", formatPath(path), err))
+		}
+
 		key, generated := template.Walk(path, includedTemplate, rules)
+		includes.Leave()
+
 		return key, interface{}(generated)
 	}
 }
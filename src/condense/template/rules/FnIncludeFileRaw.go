@@ -6,10 +6,9 @@ import (
 	"golang.org/x/tools/godoc/vfs"
 	"io"
 	"io/ioutil"
-	"path/filepath"
 )
 
-func MakeFnIncludeFileRaw(opener vfs.Opener) template.Rule {
+func MakeFnIncludeFileRaw(opener vfs.Opener, includes *Includes) template.Rule {
 	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
 		key := interface{}(nil)
 		if len(path) > 0 {
@@ -28,7 +27,7 @@ func MakeFnIncludeFileRaw(opener vfs.Opener) template.Rule {
 
 		var absPath string
 		var err error
-		if absPath, err = filepath.Abs(argString); err != nil {
+		if absPath, err = includes.Resolve(argString); err != nil {
 			panic(fmt.Errorf("Error opening imported file '%s': %s", argString, err))
 		}
 
@@ -10,7 +10,7 @@ import (
 
 func testMakeFnIncludeFileRaw(files map[string]string) template.Rule {
 	fs := mapfs.New(files)
-	return MakeFnIncludeFileRaw(fs)
+	return MakeFnIncludeFileRaw(fs, NewIncludes("/", 100))
 }
 
 func TestMakeFnIncludeFileRaw_Passthru_NonMatching(t *testing.T) {
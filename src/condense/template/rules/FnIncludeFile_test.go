@@ -2,15 +2,29 @@ package rules
 
 import (
 	"condense/template"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
+	"golang.org/x/tools/godoc/vfs"
 	"golang.org/x/tools/godoc/vfs/mapfs"
 )
 
+type countingOpener struct {
+	opener vfs.Opener
+	opened int
+}
+
+func (o *countingOpener) Open(name string) (vfs.ReadSeekCloser, error) {
+	o.opened++
+	return o.opener.Open(name)
+}
+
 func testMakeFnIncludeFile(files map[string]string, rules template.Rules) template.Rule {
 	fs := mapfs.New(files)
-	return MakeFnIncludeFile(fs, &rules)
+	return MakeFnIncludeFile(fs, NewIncludes("/", 100), &rules)
 }
 
 func TestMakeFnIncludeFile_Passthru_NonMatching(t *testing.T) {
@@ -103,7 +117,7 @@ func TestMakeFnIncludeFile_BasicRule(t *testing.T) {
 	})
 
 	fs := mapfs.New(map[string]string{"a": "{\"content\": 1}"})
-	fnIncludeFile := MakeFnIncludeFile(fs, &templateRules)
+	fnIncludeFile := MakeFnIncludeFile(fs, NewIncludes("/", 100), &templateRules)
 	input := interface{}(map[string]interface{}{
 		"Fn::IncludeFile": "/a",
 	})
@@ -118,3 +132,294 @@ func TestMakeFnIncludeFile_BasicRule(t *testing.T) {
 		t.Fatalf("FnIncludeFile did not return the expected result (%#v instead of %#v)", newNode, expected)
 	}
 }
+
+func TestMakeFnIncludeFile_Formats(t *testing.T) {
+	fnIncludeFile := testMakeFnIncludeFile(map[string]string{
+		"a.yaml":    "content:\n  - 1\n  - two\nnested:\n  3: true\n",
+		"a.yml":     "content: 1\n",
+		"a.toml":    "content = 1\n[nested]\nname = \"two\"\n",
+		"a.csv":     "name,port\nweb,80\napi,8080\n",
+		"a.env":     "# comment\nNAME=web\nexport PORT=80\nQUOTED=\"a b\\n\"\n",
+		"a.txt":     "name: web\n",
+		"a.unknown": "{\"content\": 1}",
+	}, template.Rules{})
+
+	inputs := []interface{}{
+		"/a.yaml",
+		"/a.yml",
+		"/a.toml",
+		"/a.csv",
+		"/a.env",
+		map[string]interface{}{"path": "/a.txt", "format": "yaml"},
+		map[string]interface{}{"path": "/a.unknown"},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"content": []interface{}{float64(1), "two"},
+			"nested":  map[string]interface{}{"3": true},
+		},
+		map[string]interface{}{"content": float64(1)},
+		map[string]interface{}{
+			"content": float64(1),
+			"nested":  map[string]interface{}{"name": "two"},
+		},
+		[]interface{}{
+			map[string]interface{}{"name": "web", "port": "80"},
+			map[string]interface{}{"name": "api", "port": "8080"},
+		},
+		map[string]interface{}{"NAME": "web", "PORT": "80", "QUOTED": "a b\n"},
+		map[string]interface{}{"name": "web"},
+		map[string]interface{}{"content": float64(1)},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::IncludeFile": input,
+		})
+
+		newKey, newNode := fnIncludeFile([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnIncludeFile modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnIncludeFile of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestMakeFnIncludeFile_Panic_UnknownFormat(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnIncludeFile := testMakeFnIncludeFile(map[string]string{"a": "{\"content\": 1}"}, template.Rules{})
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeFile": map[string]interface{}{"path": "/a", "format": "xml"},
+	})
+
+	_, _ = fnIncludeFile([]interface{}{"x", "y"}, input)
+	t.Fatalf("Including a file with an unknown format did not panic")
+}
+
+func TestMakeFnIncludeFile_RelativeToIncludingFile(t *testing.T) {
+	templateRules := template.Rules{}
+	fs := mapfs.New(map[string]string{
+		"project/stack.json":     "{\"Fn::IncludeFile\": \"lib/a.json\"}",
+		"project/lib/a.json":     "{\"a\": {\"Fn::IncludeFile\": \"b.json\"}}",
+		"project/lib/b.json":     "{\"b\": {\"Fn::IncludeFile\": \"../c.json\"}}",
+		"project/c.json":         "{\"c\": 1}",
+		"project/lib/c.json":     "{\"wrong\": 1}",
+		"project/lib/lib/a.json": "{\"wrong\": 1}",
+	})
+	templateRules.Attach(MakeFnIncludeFile(fs, NewIncludes("/project", 100), &templateRules))
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeFile": "stack.json",
+	})
+
+	expected := interface{}(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": float64(1)},
+		},
+	})
+
+	newKey, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if newKey != "y" {
+		t.Fatalf("FnIncludeFile modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnIncludeFile did not resolve relative to the including file (%#v instead of %#v)", newNode, expected)
+	}
+}
+
+func TestMakeFnIncludeFile_RelativeToCwd(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Could not determine the working directory: %s", err)
+	}
+
+	templateRules := template.Rules{}
+	fs := mapfs.New(map[string]string{
+		strings.TrimPrefix(filepath.Join(cwd, "a.json"), "/"): "{\"Fn::IncludeFile\": \"b.json\"}",
+		strings.TrimPrefix(filepath.Join(cwd, "b.json"), "/"): "{\"b\": 1}",
+		"project/a.json": "{\"wrong\": 1}",
+	})
+	includes := NewIncludes("/project", 100)
+	includes.RelativeToCwd = true
+	templateRules.Attach(MakeFnIncludeFile(fs, includes, &templateRules))
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeFile": "a.json",
+	})
+
+	expected := interface{}(map[string]interface{}{"b": float64(1)})
+	_, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnIncludeFile did not resolve relative to the working directory (%#v instead of %#v)", newNode, expected)
+	}
+}
+
+func TestMakeFnIncludeFile_Fragments(t *testing.T) {
+	fnIncludeFile := testMakeFnIncludeFile(map[string]string{
+		"lib/common.json": "{\"Resources\": {\"LogBucket\": {\"Type\": \"Bucket\"}, \"a/b~c\": [1, {\"d\": 2}]}}",
+		"lib/common.yaml": "Resources:\n  Queue:\n    Type: Queue\n",
+	}, template.Rules{})
+
+	inputs := []interface{}{
+		"/lib/common.json#/Resources/LogBucket",
+		"/lib/common.json#",
+		[]interface{}{"/lib/common.json", "/Resources/a~1b~0c/1/d"},
+		[]interface{}{"/lib/common.yaml", "/Resources/Queue"},
+		map[string]interface{}{"path": "/lib/common.json", "pointer": "/Resources/a~1b~0c/0"},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"Type": "Bucket"},
+		map[string]interface{}{
+			"Resources": map[string]interface{}{
+				"LogBucket": map[string]interface{}{"Type": "Bucket"},
+				"a/b~c":     []interface{}{float64(1), map[string]interface{}{"d": float64(2)}},
+			},
+		},
+		float64(2),
+		map[string]interface{}{"Type": "Queue"},
+		float64(1),
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::IncludeFile": input,
+		})
+
+		newKey, newNode := fnIncludeFile([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnIncludeFile modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnIncludeFile of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestMakeFnIncludeFile_Panic_MissingFragment(t *testing.T) {
+	inputs := []interface{}{
+		"/a#/Resources/Missing",
+		[]interface{}{"/a", "/Resources/LogBucket/Type/deeper"},
+		[]interface{}{"/a", "/List/01"},
+		[]interface{}{"/a", "/List/5"},
+		[]interface{}{"/a", "noSlash"},
+	}
+
+	fnIncludeFile := testMakeFnIncludeFile(map[string]string{
+		"a": "{\"Resources\": {\"LogBucket\": {\"Type\": \"Bucket\"}}, \"List\": [0, 1]}",
+	}, template.Rules{})
+
+	for _, input := range inputs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					// do nothing
+				}
+			}()
+
+			input := interface{}(map[string]interface{}{
+				"Fn::IncludeFile": input,
+			})
+
+			_, _ = fnIncludeFile([]interface{}{"x", "y"}, input)
+			t.Fatalf("Including a missing fragment %v did not panic", input)
+		}()
+	}
+}
+
+func TestMakeFnIncludeFile_CachesParsedFiles(t *testing.T) {
+	opener := &countingOpener{mapfs.New(map[string]string{
+		"a": "{\"One\": 1, \"Two\": 2}",
+	}), 0}
+	fnIncludeFile := MakeFnIncludeFile(opener, NewIncludes("/", 100), &template.Rules{})
+
+	for _, input := range []interface{}{"/a#/One", "/a#/Two", "/a"} {
+		_, _ = fnIncludeFile([]interface{}{"x", "y"}, interface{}(map[string]interface{}{
+			"Fn::IncludeFile": input,
+		}))
+	}
+
+	if opener.opened != 1 {
+		t.Fatalf("FnIncludeFile opened the same file %d times", opener.opened)
+	}
+}
+
+func TestMakeFnIncludeFile_Panic_Cycle(t *testing.T) {
+	templateRules := template.Rules{}
+	fs := mapfs.New(map[string]string{
+		"a.json": "{\"b\": {\"Fn::IncludeFile\": \"b.json\"}}",
+		"b.json": "{\"a\": {\"Fn::IncludeFile\": \"a.json\"}}",
+	})
+	templateRules.Attach(MakeFnIncludeFile(fs, NewIncludes("/", 100), &templateRules))
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("Including a file within itself did not panic")
+		}
+
+		expected := "a.json -> b.json -> a.json"
+		if err, ok := r.(error); !ok || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Including a file within itself did not report the chain %s (%v)", expected, r)
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeFile": "a.json",
+	})
+
+	_, _ = template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+}
+
+func TestMakeFnIncludeFile_DistinctFragments(t *testing.T) {
+	templateRules := template.Rules{}
+	fs := mapfs.New(map[string]string{
+		"a.json": "{\"One\": {\"Fn::IncludeFile\": \"a.json#/Two\"}, \"Two\": 2}",
+	})
+	templateRules.Attach(MakeFnIncludeFile(fs, NewIncludes("/", 100), &templateRules))
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeFile": "a.json#/One",
+	})
+
+	expected := interface{}(float64(2))
+	_, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnIncludeFile of distinct fragments of one file did not return the expected result (%#v instead of %#v)", newNode, expected)
+	}
+}
+
+func TestMakeFnIncludeFile_Panic_MaxDepth(t *testing.T) {
+	templateRules := template.Rules{}
+	fs := mapfs.New(map[string]string{
+		"a.json": "{\"Fn::IncludeFile\": \"b.json\"}",
+		"b.json": "{\"Fn::IncludeFile\": \"c.json\"}",
+		"c.json": "{\"c\": 1}",
+	})
+	templateRules.Attach(MakeFnIncludeFile(fs, NewIncludes("/", 2), &templateRules))
+
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeFile": "a.json",
+	})
+
+	_, _ = template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	t.Fatalf("Exceeding the maximum include depth did not panic")
+}
@@ -0,0 +1,128 @@
+package rules
+
+import (
+	"condense/template"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type DirReader interface {
+	Stater
+	ReadDir(path string) ([]os.FileInfo, error)
+}
+
+// globFiles finds the files matching an absolute pattern, where any
+// component of the pattern may contain filepath.Match wildcards.
+func globFiles(dirReader DirReader, absPattern string) ([]string, error) {
+	matches := []string{"/"}
+	for _, component := range strings.Split(strings.TrimPrefix(absPattern, "/"), "/") {
+		if component == "" {
+			continue
+		}
+
+		next := []string{}
+		for _, match := range matches {
+			if !strings.ContainsAny(component, "*?[\\") {
+				next = append(next, filepath.Join(match, component))
+				continue
+			}
+
+			if _, err := filepath.Match(component, ""); err != nil {
+				return nil, err
+			}
+
+			entries, err := dirReader.ReadDir(match)
+			if err != nil {
+				continue
+			}
+
+			for _, entry := range entries {
+				if matched, _ := filepath.Match(component, entry.Name()); matched {
+					next = append(next, filepath.Join(match, entry.Name()))
+				}
+			}
+		}
+		matches = next
+	}
+
+	files := []string{}
+	for _, match := range matches {
+		if info, err := dirReader.Stat(match); err == nil && !info.IsDir() {
+			files = append(files, match)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func MakeFnIncludeGlob(dirReader DirReader, includes *Includes, rules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		argsInterface, ok := singleKey(node, "Fn::IncludeGlob")
+		if !ok {
+			return key, node //passthru
+		}
+
+		var pattern string
+		asArray := false
+		if pattern, ok = argsInterface.(string); !ok {
+			var args []interface{}
+			if args, ok = argsInterface.([]interface{}); !ok || len(args) != 2 {
+				return key, node //passthru
+			}
+
+			if pattern, ok = args[0].(string); !ok {
+				return key, node //passthru
+			}
+
+			switch args[1] {
+			case "object":
+			case "array":
+				asArray = true
+			default:
+				return key, node //passthru
+			}
+		}
+
+		absPattern, err := includes.Resolve(pattern)
+		if err != nil {
+			panic(fmt.Errorf("Error including files matching '%s' at '%s': %s", pattern, formatPath(path), err))
+		}
+
+		var files []string
+		if files, err = globFiles(dirReader, absPattern); err != nil {
+			panic(fmt.Errorf("Error including files matching '%s' at '%s': %s", pattern, formatPath(path), err))
+		}
+
+		var generated interface{}
+		if asArray {
+			included := make([]interface{}, len(files))
+			for i, file := range files {
+				included[i] = map[string]interface{}{"Fn::IncludeFile": file}
+			}
+			generated = included
+		} else {
+			included := make(map[string]interface{}, len(files))
+			for _, file := range files {
+				base := filepath.Base(file)
+				base = strings.TrimSuffix(base, filepath.Ext(base))
+				if _, exists := included[base]; exists {
+					panic(fmt.Errorf("Multiple files named '%s' match '%s' at '%s'", base, pattern, formatPath(path)))
+				}
+
+				included[base] = map[string]interface{}{"Fn::IncludeFile": file}
+			}
+			generated = included
+		}
+
+		return template.Walk(path, generated, rules)
+	}
+}
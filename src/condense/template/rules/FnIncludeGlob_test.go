@@ -0,0 +1,113 @@
+package rules
+
+import (
+	"condense/template"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/godoc/vfs/mapfs"
+)
+
+func testMakeFnIncludeGlob(files map[string]string) template.Rule {
+	templateRules := template.Rules{}
+	fs := mapfs.New(files)
+	includes := NewIncludes("/", 100)
+	templateRules.Attach(MakeFnIncludeFile(fs, includes, &templateRules))
+	return MakeFnIncludeGlob(fs, includes, &templateRules)
+}
+
+func TestFnIncludeGlob_Passthru_NonMatching(t *testing.T) {
+	fnIncludeGlob := testMakeFnIncludeGlob(map[string]string{})
+	testRule_Passthru_NonMatching(fnIncludeGlob, "Fn::IncludeGlob", t)
+}
+
+func TestFnIncludeGlob_Passthru_BadArguments(t *testing.T) {
+	fnIncludeGlob := testMakeFnIncludeGlob(map[string]string{"a.json": "1"})
+
+	inputs := []interface{}{
+		float64(1),
+		[]interface{}{"*.json"},
+		[]interface{}{"*.json", "set"},
+		[]interface{}{float64(1), "array"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::IncludeGlob": input,
+		})
+
+		newKey, newNode := fnIncludeGlob([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnIncludeGlob modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnIncludeGlob with bad arguments %v modified the data (%#v instead of %#v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnIncludeGlob_Panic_DuplicateNames(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnIncludeGlob := testMakeFnIncludeGlob(map[string]string{
+		"alarms/a.json": "1",
+		"alarms/a.yaml": "2",
+	})
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeGlob": "alarms/a.*",
+	})
+
+	_, _ = fnIncludeGlob([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnIncludeGlob of duplicate base names did not panic")
+}
+
+func TestFnIncludeGlob_Basic(t *testing.T) {
+	fnIncludeGlob := testMakeFnIncludeGlob(map[string]string{
+		"alarms/HighCPU.json":      "{\"Type\": \"Alarm\", \"Metric\": \"CPU\"}",
+		"alarms/LowDisk.yaml":      "Type: Alarm\nMetric: Disk\n",
+		"alarms/README.md":         "Not included",
+		"alarms/nested/Deep.json":  "{\"Type\": \"Alarm\", \"Metric\": \"Deep\"}",
+		"services/a/function.json": "\"a\"",
+		"services/b/function.json": "\"b\"",
+	})
+
+	inputs := []interface{}{
+		"alarms/*.json",
+		"/alarms/*.[jy]*",
+		[]interface{}{"services/*/function.json", "array"},
+		"missing/*.json",
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"HighCPU": map[string]interface{}{"Type": "Alarm", "Metric": "CPU"},
+		},
+		map[string]interface{}{
+			"HighCPU": map[string]interface{}{"Type": "Alarm", "Metric": "CPU"},
+			"LowDisk": map[string]interface{}{"Type": "Alarm", "Metric": "Disk"},
+		},
+		[]interface{}{"a", "b"},
+		map[string]interface{}{},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::IncludeGlob": input,
+		})
+
+		newKey, newNode := fnIncludeGlob([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnIncludeGlob modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnIncludeGlob of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
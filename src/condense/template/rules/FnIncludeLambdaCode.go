@@ -0,0 +1,426 @@
+package rules
+
+import (
+	"archive/zip"
+	"bytes"
+	"condense/template"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"golang.org/x/tools/godoc/vfs"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InlineLambdaCodeLimit is the maximum size, in bytes, of Lambda code
+// given inline via ZipFile.
+const InlineLambdaCodeLimit = 4096
+
+type DirOpener interface {
+	vfs.Opener
+	DirReader
+}
+
+type Creator interface {
+	Create(path string) (io.WriteCloser, error)
+}
+
+var lambdaLanguages = map[string]string{
+	".py":  "python",
+	".js":  "node",
+	".mjs": "node",
+	".cjs": "node",
+}
+
+type codeState int
+
+const (
+	inCode codeState = iota
+	inString
+	inBlockComment
+)
+
+// scanCodeLine follows the string and comment state through a line of
+// source, starting from state (where delimiter closes any open string),
+// and reports whether the line has any code outside of comments, and
+// where any block comment left open at the end of the line began. Only
+// strings and comments which may span lines are carried forward.
+func scanCodeLine(line string, language string, state codeState, delimiter string) (codeState, string, bool, int) {
+	quotes := []string{"'", "\"", "`"}
+	if language == "python" {
+		quotes = []string{"\"\"\"", "'''", "'", "\""}
+	}
+
+	hasCode := false
+	commentStart := -1
+	for i := 0; i < len(line); {
+		switch state {
+		case inString:
+			if line[i] == '\\' {
+				if i+1 == len(line) {
+					return state, delimiter, true, -1 // an escaped newline continues any string
+				}
+				i += 2
+			} else if strings.HasPrefix(line[i:], delimiter) {
+				state, i = inCode, i+len(delimiter)
+			} else {
+				i++
+			}
+		case inBlockComment:
+			if strings.HasPrefix(line[i:], "*/") {
+				state, i = inCode, i+2
+			} else {
+				i++
+			}
+		default:
+			rest := line[i:]
+			if (language == "python" && strings.HasPrefix(rest, "#")) || (language == "node" && strings.HasPrefix(rest, "//")) {
+				return inCode, "", hasCode, -1
+			}
+
+			if language == "node" && strings.HasPrefix(rest, "/*") {
+				state, commentStart, i = inBlockComment, i, i+2
+				continue
+			}
+
+			if line[i] != ' ' && line[i] != '\t' && line[i] != '\r' {
+				hasCode = true
+			}
+
+			matched := false
+			for _, quote := range quotes {
+				if strings.HasPrefix(rest, quote) {
+					state, delimiter, i = inString, quote, i+len(quote)
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				i++
+			}
+		}
+	}
+
+	if state == inString && (delimiter == "'" || delimiter == "\"") {
+		return inCode, "", hasCode, -1 // single-line strings end with the line
+	}
+
+	if state != inBlockComment {
+		commentStart = -1
+	}
+
+	return state, delimiter, hasCode, commentStart
+}
+
+// minifyLambdaCode conservatively removes lines which are entirely comment
+// or whitespace, and trailing whitespace, leaving everything else
+// (including indentation, and the contents of strings which span lines)
+// untouched.
+func minifyLambdaCode(code string, language string) (string, error) {
+	if language != "python" && language != "node" {
+		return "", fmt.Errorf("Unable to minify code in language '%s'", language)
+	}
+
+	lines := []string{}
+	state, delimiter := inCode, ""
+	for i, line := range strings.Split(code, "\n") {
+		startState := state
+		var hasCode bool
+		var commentStart int
+		state, delimiter, hasCode, commentStart = scanCodeLine(line, language, state, delimiter)
+
+		if state != inString {
+			line = strings.TrimRight(line, " \t\r")
+		}
+
+		if startState == inString {
+			lines = append(lines, line)
+			continue
+		}
+
+		if i == 0 && strings.HasPrefix(line, "#!") {
+			lines = append(lines, line)
+			continue
+		}
+
+		if !hasCode {
+			continue // the line is entirely comment or whitespace
+		}
+
+		// block comments spanning lines are removed whole, as the lines
+		// between are, keeping only the code around them
+		if commentStart >= 0 {
+			line = strings.TrimRight(line[:commentStart], " \t")
+		}
+
+		if startState == inBlockComment {
+			line = line[strings.Index(line, "*/")+2:]
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// zipDirectory builds a zip of every file beneath absPath (other than
+// excludePath, where the zip itself may be written), which is byte-for-byte
+// identical given identical file names and contents.
+func zipDirectory(fs DirOpener, absPath string, excludePath string) ([]byte, error) {
+	files := []string{}
+	var collect func(dir string) error
+	collect = func(dir string) error {
+		entries, err := fs.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			entryPath := filepath.Join(dir, entry.Name())
+			if entry.IsDir() {
+				if err := collect(entryPath); err != nil {
+					return err
+				}
+			} else if entryPath != excludePath {
+				files = append(files, entryPath)
+			}
+		}
+
+		return nil
+	}
+
+	if err := collect(absPath); err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	buffer := bytes.Buffer{}
+	archive := zip.NewWriter(&buffer)
+	for _, file := range files {
+		info, err := fs.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+
+		relPath, err := filepath.Rel(absPath, file)
+		if err != nil {
+			return nil, err
+		}
+
+		header := &zip.FileHeader{
+			Name:   filepath.ToSlash(relPath),
+			Method: zip.Deflate,
+		}
+		header.Modified = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+		if info.Mode()&0111 != 0 {
+			header.SetMode(0755)
+		} else {
+			header.SetMode(0644)
+		}
+
+		writer, err := archive.CreateHeader(header)
+		if err != nil {
+			return nil, err
+		}
+
+		reader, err := fs.Open(file)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = io.Copy(writer, reader)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+type lambdaCodeArgs struct {
+	path     string
+	minify   bool
+	language string
+	zip      bool
+	output   string
+}
+
+// includeLambdaCodeArgs accepts either a filename, or an object of the form
+// {"path": filename, "minify": bool, "language": language}, or
+// {"path": directory, "zip": true, "output": filename}, where all but path
+// are optional.
+func includeLambdaCodeArgs(argInterface interface{}) (args lambdaCodeArgs, ok bool) {
+	if args.path, ok = argInterface.(string); ok {
+		return args, true
+	}
+
+	var argsMap map[string]interface{}
+	if argsMap, ok = argInterface.(map[string]interface{}); !ok {
+		return args, false
+	}
+
+	for argKey, argValue := range argsMap {
+		switch argKey {
+		case "path":
+			args.path, ok = argValue.(string)
+		case "minify":
+			args.minify, ok = argValue.(bool)
+		case "language":
+			args.language, ok = argValue.(string)
+		case "zip":
+			args.zip, ok = argValue.(bool)
+		case "output":
+			args.output, ok = argValue.(string)
+		default:
+			ok = false
+		}
+
+		if !ok {
+			return args, false
+		}
+	}
+
+	if args.path == "" || (args.zip && (args.minify || args.language != "")) || (!args.zip && args.output != "") {
+		return args, false
+	}
+
+	if args.language == "" {
+		args.language = lambdaLanguages[strings.ToLower(filepath.Ext(args.path))]
+	}
+
+	return args, true
+}
+
+func writeLambdaZip(creator Creator, outputPath string, data []byte) {
+	writer, err := creator.Create(outputPath)
+	if err != nil {
+		panic(fmt.Errorf("Error writing Lambda code '%s': %s", outputPath, err))
+	}
+
+	_, err = writer.Write(data)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		panic(fmt.Errorf("Error writing Lambda code '%s': %s", outputPath, err))
+	}
+}
+
+func MakeFnIncludeLambdaCode(fs DirOpener, creator Creator, includes *Includes) template.Rule {
+	// templates may be processed more than once, so each zip is only
+	// written once (and two different zips may not share an output)
+	written := map[string][sha256.Size]byte{}
+
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		argInterface, ok := singleKey(node, "Fn::IncludeLambdaCode")
+		if !ok {
+			return key, node //passthru
+		}
+
+		var args lambdaCodeArgs
+		if args, ok = includeLambdaCodeArgs(argInterface); !ok {
+			return key, node //passthru
+		}
+
+		var absPath string
+		var err error
+		if absPath, err = includes.Resolve(args.path); err != nil {
+			panic(fmt.Errorf("Error opening Lambda code '%s': %s", args.path, err))
+		}
+
+		if args.zip {
+			var outputPath string
+			if args.output != "" {
+				if outputPath, err = includes.Resolve(args.output); err != nil {
+					panic(fmt.Errorf("Error writing Lambda code '%s': %s", args.output, err))
+				}
+			}
+
+			var data []byte
+			if data, err = zipDirectory(fs, absPath, outputPath); err != nil {
+				panic(fmt.Errorf("Error zipping Lambda code '%s': %s", absPath, err))
+			}
+
+			sum := sha256.Sum256(data)
+			if outputPath != "" {
+				if writtenSum, ok := written[outputPath]; ok {
+					if writtenSum != sum {
+						panic(fmt.Errorf("Lambda code '%s' at '%s' would overwrite a different zip in '%s'", args.path, formatPath(path), outputPath))
+					}
+				} else {
+					writeLambdaZip(creator, outputPath, data)
+					written[outputPath] = sum
+				}
+			}
+
+			return key, interface{}(map[string]interface{}{
+				"Hash":       hex.EncodeToString(sum[:]),
+				"CodeSha256": base64.StdEncoding.EncodeToString(sum[:]),
+				"Size":       float64(len(data)),
+			})
+		}
+
+		var dataStream io.Reader
+		if dataStream, err = fs.Open(absPath); err != nil {
+			panic(fmt.Errorf("Error opening Lambda code '%s': %s", absPath, err))
+		}
+
+		var data []byte
+		if data, err = ioutil.ReadAll(dataStream); err != nil {
+			panic(fmt.Errorf("Error loading Lambda code '%s': %s", args.path, err))
+		}
+
+		code := string(data)
+		if args.minify {
+			if code, err = minifyLambdaCode(code, args.language); err != nil {
+				panic(fmt.Errorf("Error minifying Lambda code '%s' at '%s': %s", args.path, formatPath(path), err))
+			}
+		}
+
+		if len(code) > InlineLambdaCodeLimit {
+			panic(fmt.Errorf("Lambda code '%s' at '%s' is %d bytes, exceeding the inline limit of %d bytes", args.path, formatPath(path), len(code), InlineLambdaCodeLimit))
+		}
+
+		return key, interface{}(code)
+	}
+}
+
+// OSCreator creates files on the local filesystem.
+type OSCreator struct{}
+
+func (OSCreator) Create(path string) (io.WriteCloser, error) {
+	return os.Create(path)
+}
+
+type discardCloser struct {
+	io.Writer
+}
+
+func (discardCloser) Close() error {
+	return nil
+}
+
+// DiscardCreator accepts, but never writes, any file.
+type DiscardCreator struct{}
+
+func (DiscardCreator) Create(path string) (io.WriteCloser, error) {
+	return discardCloser{ioutil.Discard}, nil
+}
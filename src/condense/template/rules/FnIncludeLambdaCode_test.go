@@ -0,0 +1,311 @@
+package rules
+
+import (
+	"archive/zip"
+	"bytes"
+	"condense/template"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/godoc/vfs/mapfs"
+)
+
+type memoryFile struct {
+	bytes.Buffer
+}
+
+func (f *memoryFile) Close() error {
+	return nil
+}
+
+type memoryCreator map[string]*memoryFile
+
+func (c memoryCreator) Create(path string) (io.WriteCloser, error) {
+	c[path] = &memoryFile{}
+	return c[path], nil
+}
+
+func testMakeFnIncludeLambdaCode(files map[string]string, creator Creator) template.Rule {
+	fs := mapfs.New(files)
+	return MakeFnIncludeLambdaCode(fs, creator, NewIncludes("/", 100))
+}
+
+func TestFnIncludeLambdaCode_Passthru_NonMatching(t *testing.T) {
+	fnIncludeLambdaCode := testMakeFnIncludeLambdaCode(map[string]string{}, memoryCreator{})
+	testRule_Passthru_NonMatching(fnIncludeLambdaCode, "Fn::IncludeLambdaCode", t)
+}
+
+func TestFnIncludeLambdaCode_Passthru_BadArguments(t *testing.T) {
+	fnIncludeLambdaCode := testMakeFnIncludeLambdaCode(map[string]string{"a.py": "pass\n"}, memoryCreator{})
+
+	inputs := []interface{}{
+		float64(1),
+		map[string]interface{}{"minify": true},
+		map[string]interface{}{"path": "/a.py", "minify": "yes"},
+		map[string]interface{}{"path": "/a.py", "other": true},
+		map[string]interface{}{"path": "/a.py", "output": "/a.zip"},
+		map[string]interface{}{"path": "/", "zip": true, "minify": true},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::IncludeLambdaCode": input,
+		})
+
+		newKey, newNode := fnIncludeLambdaCode([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnIncludeLambdaCode modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnIncludeLambdaCode with bad arguments %v modified the data (%#v instead of %#v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnIncludeLambdaCode_Panic_TooLarge(t *testing.T) {
+	inputs := []interface{}{
+		"/large.py",
+		map[string]interface{}{"path": "/large.py", "minify": true},
+	}
+
+	fnIncludeLambdaCode := testMakeFnIncludeLambdaCode(map[string]string{
+		"large.py": strings.Repeat("x = 1\n", 1000),
+	}, memoryCreator{})
+
+	for _, input := range inputs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					// do nothing
+				}
+			}()
+
+			input := interface{}(map[string]interface{}{
+				"Fn::IncludeLambdaCode": input,
+			})
+
+			_, _ = fnIncludeLambdaCode([]interface{}{"x", "y"}, input)
+			t.Fatalf("FnIncludeLambdaCode of %v exceeding the inline limit did not panic", input)
+		}()
+	}
+}
+
+func TestFnIncludeLambdaCode_Panic_UnknownLanguage(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnIncludeLambdaCode := testMakeFnIncludeLambdaCode(map[string]string{"a.rb": "puts 1\n"}, memoryCreator{})
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeLambdaCode": map[string]interface{}{"path": "/a.rb", "minify": true},
+	})
+
+	_, _ = fnIncludeLambdaCode([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnIncludeLambdaCode minifying an unknown language did not panic")
+}
+
+func TestFnIncludeLambdaCode_Basic(t *testing.T) {
+	fnIncludeLambdaCode := testMakeFnIncludeLambdaCode(map[string]string{
+		"index.py": "#!/usr/bin/env python\n# A comment\n\ndef handler(event, context):  \n    # Indented comment\n    return 1 # trailing\n",
+		"index.js": "/**\n * A comment\n */\nexports.handler = async () => {\n  // A comment\n\n  return 1; /* inline */\n};\n",
+		"index.sh": "# kept\n",
+	}, memoryCreator{})
+
+	inputs := []interface{}{
+		"/index.py",
+		map[string]interface{}{"path": "/index.py", "minify": true},
+		map[string]interface{}{"path": "/index.js", "minify": true},
+		map[string]interface{}{"path": "/index.sh", "minify": true, "language": "python"},
+	}
+
+	expected := []interface{}{
+		"#!/usr/bin/env python\n# A comment\n\ndef handler(event, context):  \n    # Indented comment\n    return 1 # trailing\n",
+		"#!/usr/bin/env python\ndef handler(event, context):\n    return 1 # trailing\n",
+		"exports.handler = async () => {\n  return 1; /* inline */\n};\n",
+		"\n",
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::IncludeLambdaCode": input,
+		})
+
+		newKey, newNode := fnIncludeLambdaCode([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnIncludeLambdaCode modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnIncludeLambdaCode of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnIncludeLambdaCode_Zip(t *testing.T) {
+	files := map[string]string{
+		"lambda/index.py":        "import lib\n",
+		"lambda/lib/__init__.py": "",
+		"lambda/lib/util.py":     "x = 1\n",
+		"other/ignored.py":       "",
+	}
+
+	creator := memoryCreator{}
+	fnIncludeLambdaCode := testMakeFnIncludeLambdaCode(files, creator)
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeLambdaCode": map[string]interface{}{"path": "/lambda", "zip": true, "output": "/build/lambda.zip"},
+	})
+
+	newKey, newNode := fnIncludeLambdaCode([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnIncludeLambdaCode modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	_, repeatNode := testMakeFnIncludeLambdaCode(files, memoryCreator{})([]interface{}{"x", "y"}, input)
+	if !reflect.DeepEqual(newNode, repeatNode) {
+		t.Fatalf("FnIncludeLambdaCode zip was not deterministic (%#v instead of %#v)", repeatNode, newNode)
+	}
+
+	written, ok := creator["/build/lambda.zip"]
+	if !ok {
+		t.Fatalf("FnIncludeLambdaCode did not write the zip file")
+	}
+
+	result := newNode.(map[string]interface{})
+	if result["Size"] != float64(written.Len()) {
+		t.Fatalf("FnIncludeLambdaCode reported the wrong size (%v instead of %v)", result["Size"], written.Len())
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(written.Bytes()), int64(written.Len()))
+	if err != nil {
+		t.Fatalf("FnIncludeLambdaCode did not write a valid zip file: %s", err)
+	}
+
+	names := []string{}
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+
+	expectedNames := []string{"index.py", "lib/__init__.py", "lib/util.py"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("FnIncludeLambdaCode zipped the wrong files (%v instead of %v)", names, expectedNames)
+	}
+
+	reader, _ := archive.File[0].Open()
+	content, _ := ioutil.ReadAll(reader)
+	if string(content) != files["lambda/index.py"] {
+		t.Fatalf("FnIncludeLambdaCode zipped the wrong content (%q instead of %q)", content, files["lambda/index.py"])
+	}
+}
+
+func TestFnIncludeLambdaCode_MinifyPreservesStrings(t *testing.T) {
+	inputs := []string{
+		"def handler(event, context):\n    \"\"\"Usage:  \n# not a comment\n\n    \"\"\"  \n    # a comment\n    s = '''\n// kept\n'''\n    t = \"a # b\" # c\n    u = \"line \\\n# continued\"\n\n    return s\n",
+		"const s = `\n// not a comment\n\n  `;\n/* a\n   comment */\nconst t = '/* not a comment';\n// a comment\nconst u = \"`\"; /* x */ const v = 1;\n",
+		"/* a */ const x = 1; /* b\n c */\n/* d\n */ const y = x; /* e\n*/ const z = y; /* f */\nmodule.exports = z;\n",
+	}
+
+	languages := []string{"python", "node", "node"}
+
+	expected := []string{
+		"def handler(event, context):\n    \"\"\"Usage:  \n# not a comment\n\n    \"\"\"\n    s = '''\n// kept\n'''\n    t = \"a # b\" # c\n    u = \"line \\\n# continued\"\n    return s\n",
+		"const s = `\n// not a comment\n\n  `;\nconst t = '/* not a comment';\nconst u = \"`\"; /* x */ const v = 1;\n",
+		"/* a */ const x = 1;\n const y = x;\n const z = y; /* f */\nmodule.exports = z;\n",
+	}
+
+	for i, input := range inputs {
+		minified, err := minifyLambdaCode(input, languages[i])
+		if err != nil {
+			t.Fatalf("Minifying %q returned an error: %s", input, err)
+		}
+
+		if minified != expected[i] {
+			t.Fatalf("Minifying %q did not return the expected result (%q instead of %q)", input, minified, expected[i])
+		}
+	}
+}
+
+func TestFnIncludeLambdaCode_ZipWrittenOnce(t *testing.T) {
+	files := map[string]string{
+		"a/index.py": "a = 1\n",
+		"b/index.py": "b = 1\n",
+	}
+
+	creator := memoryCreator{}
+	fnIncludeLambdaCode := testMakeFnIncludeLambdaCode(files, creator)
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeLambdaCode": map[string]interface{}{"path": "/a", "zip": true, "output": "/lambda.zip"},
+	})
+
+	_, _ = fnIncludeLambdaCode([]interface{}{"x", "y"}, input)
+	delete(creator, "/lambda.zip")
+
+	_, _ = fnIncludeLambdaCode([]interface{}{"x", "y"}, input)
+	if _, ok := creator["/lambda.zip"]; ok {
+		t.Fatalf("FnIncludeLambdaCode wrote the same zip more than once")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	_, _ = fnIncludeLambdaCode([]interface{}{"x", "y"}, interface{}(map[string]interface{}{
+		"Fn::IncludeLambdaCode": map[string]interface{}{"path": "/b", "zip": true, "output": "/lambda.zip"},
+	}))
+	t.Fatalf("FnIncludeLambdaCode overwriting a zip with different contents did not panic")
+}
+
+type mapCreator map[string]string
+
+type mapFile struct {
+	bytes.Buffer
+	files mapCreator
+	path  string
+}
+
+func (f *mapFile) Close() error {
+	f.files[f.path] = f.String()
+	return nil
+}
+
+func (c mapCreator) Create(path string) (io.WriteCloser, error) {
+	return &mapFile{files: c, path: strings.TrimPrefix(path, "/")}, nil
+}
+
+func TestFnIncludeLambdaCode_ZipOutputWithin(t *testing.T) {
+	files := map[string]string{
+		"fn/index.py":  "a = 1\n",
+		"fn/build.zip": "a stale zip",
+	}
+
+	_, expectedNode := testMakeFnIncludeLambdaCode(map[string]string{"fn/index.py": "a = 1\n"}, memoryCreator{})(
+		[]interface{}{"x", "y"},
+		interface{}(map[string]interface{}{
+			"Fn::IncludeLambdaCode": map[string]interface{}{"path": "/fn", "zip": true},
+		}),
+	)
+
+	// written into the same files as are zipped, as on a real filesystem
+	fnIncludeLambdaCode := testMakeFnIncludeLambdaCode(files, mapCreator(files))
+
+	input := interface{}(map[string]interface{}{
+		"Fn::IncludeLambdaCode": map[string]interface{}{"path": "/fn", "zip": true, "output": "/fn/build.zip"},
+	})
+
+	for pass := 0; pass < 2; pass++ {
+		_, newNode := fnIncludeLambdaCode([]interface{}{"x", "y"}, input)
+		if !reflect.DeepEqual(newNode, expectedNode) {
+			t.Fatalf("FnIncludeLambdaCode with its output inside the zipped directory did not return the expected result (%#v instead of %#v)", newNode, expectedNode)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package rules
+
+import (
+	"condense/template"
+	"fmt"
+	"golang.org/x/tools/godoc/vfs"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*(\w+)\s+([^}]*?)\s*\}\}`)
+
+// placeholderNode converts a placeholder such as "Ref VPCName" or
+// "GetAtt Stack Outputs.X" into the equivalent intrinsic function.
+func placeholderNode(fnName string, argsString string) (interface{}, bool) {
+	args := strings.Fields(argsString)
+
+	switch fnName {
+	case "Ref":
+		if len(args) != 1 {
+			return nil, false
+		}
+
+		return map[string]interface{}{"Ref": args[0]}, true
+	case "GetAtt":
+		if len(args) == 1 {
+			args = strings.SplitN(args[0], ".", 2)
+		}
+
+		if len(args) != 2 {
+			return nil, false
+		}
+
+		return map[string]interface{}{"Fn::GetAtt": []interface{}{args[0], args[1]}}, true
+	}
+
+	return nil, false
+}
+
+// includeTemplateArgs accepts either a filename, or an object of the form
+// {"path": filename, "base64": bool}, where base64 is optional.
+func includeTemplateArgs(argInterface interface{}) (filename string, base64 bool, ok bool) {
+	if filename, ok = argInterface.(string); ok {
+		return filename, false, true
+	}
+
+	var args map[string]interface{}
+	if args, ok = argInterface.(map[string]interface{}); !ok {
+		return "", false, false
+	}
+
+	for argKey := range args {
+		if argKey != "path" && argKey != "base64" {
+			return "", false, false
+		}
+	}
+
+	if filename, ok = args["path"].(string); !ok {
+		return "", false, false
+	}
+
+	if base64Interface, hasBase64 := args["base64"]; hasBase64 {
+		if base64, ok = base64Interface.(bool); !ok {
+			return "", false, false
+		}
+	}
+
+	return filename, base64, true
+}
+
+func MakeFnIncludeTemplate(opener vfs.Opener, includes *Includes, rules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		argInterface, ok := singleKey(node, "Fn::IncludeTemplate")
+		if !ok {
+			return key, node //passthru
+		}
+
+		var argString string
+		var base64 bool
+		if argString, base64, ok = includeTemplateArgs(argInterface); !ok {
+			return key, node //passthru
+		}
+
+		var absPath string
+		var err error
+		if absPath, err = includes.Resolve(argString); err != nil {
+			panic(fmt.Errorf("Error opening imported file '%s': %s", argString, err))
+		}
+
+		var dataStream io.Reader
+		if dataStream, err = opener.Open(absPath); err != nil {
+			panic(fmt.Errorf("Error opening imported file '%s': %s", absPath, err))
+		}
+
+		var data []byte
+		if data, err = ioutil.ReadAll(dataStream); err != nil {
+			panic(fmt.Errorf("Error loading imported file '%s': %s", argString, err))
+		}
+
+		text := string(data)
+		pieces := []interface{}{}
+		offset := 0
+		for _, match := range placeholderPattern.FindAllStringSubmatchIndex(text, -1) {
+			fnName := text[match[2]:match[3]]
+			if fnName != "Ref" && fnName != "GetAtt" {
+				continue //not ours (eg: another templating language), leave it verbatim
+			}
+
+			placeholder := text[match[0]:match[1]]
+			pieces = append(pieces, text[offset:match[0]])
+			offset = match[1]
+
+			var intrinsic interface{}
+			if intrinsic, ok = placeholderNode(fnName, text[match[4]:match[5]]); !ok {
+				panic(fmt.Errorf("Invalid placeholder '%s' in imported file '%s'", placeholder, argString))
+			}
+
+			_, resolved := template.Walk(path, intrinsic, rules)
+			if resolvedString, ok := formatScalar(resolved); ok {
+				pieces = append(pieces, resolvedString)
+			} else if isIntrinsic(resolved) {
+				pieces = append(pieces, resolved)
+			} else {
+				panic(fmt.Errorf("Placeholder '%s' in imported file '%s' did not resolve to a scalar value", placeholder, argString))
+			}
+		}
+		pieces = append(pieces, text[offset:])
+
+		generated := joinPieces(pieces)
+		if base64 {
+			generated = map[string]interface{}{"Fn::Base64": generated}
+		}
+
+		return key, generated
+	}
+}
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fallbackmap"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/godoc/vfs/mapfs"
+)
+
+func testMakeFnIncludeTemplate(files map[string]string, stack deepstack.DeepStack) template.Rule {
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(MakeFnGetAtt(&stack, &templateRules))
+
+	fs := mapfs.New(files)
+	return MakeFnIncludeTemplate(fs, NewIncludes("/", 100), &templateRules)
+}
+
+func TestFnIncludeTemplate_Passthru_NonMatching(t *testing.T) {
+	fnIncludeTemplate := testMakeFnIncludeTemplate(map[string]string{}, deepstack.DeepStack{})
+	testRule_Passthru_NonMatching(fnIncludeTemplate, "Fn::IncludeTemplate", t)
+}
+
+func TestFnIncludeTemplate_Passthru_BadArguments(t *testing.T) {
+	fnIncludeTemplate := testMakeFnIncludeTemplate(map[string]string{"a": "content"}, deepstack.DeepStack{})
+
+	inputs := []interface{}{
+		float64(1),
+		map[string]interface{}{"path": "/a", "base64": "yes"},
+		map[string]interface{}{"path": "/a", "other": true},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::IncludeTemplate": input,
+		})
+
+		newKey, newNode := fnIncludeTemplate([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnIncludeTemplate modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnIncludeTemplate with bad arguments %v modified the data (%#v instead of %#v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnIncludeTemplate_Panic_BadPlaceholder(t *testing.T) {
+	inputs := []string{
+		"{{Ref Too Many}}",
+		"{{GetAtt NoAttribute}}",
+		"{{Ref Object}}",
+	}
+
+	stack := deepstack.DeepStack{}
+	stack.Push(fallbackmap.DeepMap(map[string]interface{}{
+		"Object": map[string]interface{}{"a": "b"},
+	}))
+
+	for _, input := range inputs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					// do nothing
+				}
+			}()
+
+			fnIncludeTemplate := testMakeFnIncludeTemplate(map[string]string{"a": input}, stack)
+			_, _ = fnIncludeTemplate([]interface{}{"x", "y"}, interface{}(map[string]interface{}{
+				"Fn::IncludeTemplate": "/a",
+			}))
+			t.Fatalf("FnIncludeTemplate with placeholder %v did not panic", input)
+		}()
+	}
+}
+
+func TestFnIncludeTemplate_Basic(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	stack.Push(fallbackmap.DeepMap(map[string]interface{}{
+		"Name":  "web",
+		"Port":  float64(80),
+		"Stack": map[string]interface{}{"Outputs": map[string]interface{}{"X": "out"}},
+	}))
+
+	fnIncludeTemplate := testMakeFnIncludeTemplate(map[string]string{
+		"local.sh":  "echo {{Ref Name}}:{{ Ref Port }} {{GetAtt Stack Outputs.X}}\n",
+		"remote.sh": "echo {{Ref Name}} {{Ref AWS::Region}} {{GetAtt Other.Outputs.Y}}\n",
+		"other.sh":  "echo {{ range .Items }}{{Ref Name}}{{ end }} {{Sub ${Name}}}\n",
+	}, stack)
+
+	inputs := []interface{}{
+		"/local.sh",
+		"/remote.sh",
+		map[string]interface{}{"path": "/local.sh", "base64": true},
+		"/other.sh",
+	}
+
+	expected := []interface{}{
+		"echo web:80 out\n",
+		map[string]interface{}{
+			"Fn::Join": []interface{}{"", []interface{}{
+				"echo web ",
+				map[string]interface{}{"Ref": "AWS::Region"},
+				" ",
+				map[string]interface{}{"Fn::GetAtt": []interface{}{"Other", "Outputs.Y"}},
+				"\n",
+			}},
+		},
+		map[string]interface{}{"Fn::Base64": "echo web:80 out\n"},
+		"echo {{ range .Items }}web{{ end }} {{Sub ${Name}}}\n",
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::IncludeTemplate": input,
+		})
+
+		newKey, newNode := fnIncludeTemplate([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnIncludeTemplate modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnIncludeTemplate of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+func encodeJsonString(value string) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		panic(err)
+	}
+
+	return string(bytes.TrimRight(buffer.Bytes(), "\n"))
+}
+
+// stringIntrinsics are the CloudFormation intrinsics which (usually)
+// resolve to strings, and so may be spliced into a JSON string. Fn::If and
+// Fn::Select are not among them, as they may resolve to any type.
+var stringIntrinsics = map[string]bool{
+	"Ref":             true,
+	"Fn::Base64":      true,
+	"Fn::FindInMap":   true,
+	"Fn::GetAtt":      true,
+	"Fn::ImportValue": true,
+	"Fn::Join":        true,
+	"Fn::Sub":         true,
+}
+
+// listIntrinsics are the CloudFormation intrinsics which resolve to lists,
+// and so can never be spliced into a JSON string.
+var listIntrinsics = map[string]bool{
+	"Fn::Cidr":   true,
+	"Fn::GetAZs": true,
+	"Fn::Split":  true,
+}
+
+func intrinsicName(value interface{}) string {
+	for name := range value.(map[string]interface{}) {
+		return name
+	}
+
+	return ""
+}
+
+// jsonPieces serializes a value into a list of pieces of JSON text.
+// Unresolved CloudFormation intrinsics are left as pieces of their own
+// (wrapped in quotes, as they are expected to resolve to strings), so that
+// they can be spliced in via Fn::Join. The resolved values are not escaped,
+// so they must not contain quotes or backslashes.
+//
+// ok will be false if the value contains other intrinsics, which may yet
+// be resolved by a later pass.
+func jsonPieces(path []interface{}, value interface{}) (pieces []interface{}, ok bool) {
+	if isIntrinsic(value) {
+		name := intrinsicName(value)
+		if listIntrinsics[name] {
+			panic(fmt.Errorf("Fn::ToJsonString at '%s' cannot embed %s, which returns a list rather than a string", formatPath(path), name))
+		}
+
+		if !stringIntrinsics[name] {
+			return nil, false
+		}
+
+		return []interface{}{"\"", value, "\""}, true
+	}
+
+	switch typed := value.(type) {
+	case nil:
+		return []interface{}{"null"}, true
+	case string:
+		return []interface{}{encodeJsonString(typed)}, true
+	case []interface{}:
+		pieces := []interface{}{"["}
+		for i, item := range typed {
+			if i > 0 {
+				pieces = append(pieces, ",")
+			}
+
+			itemPieces, ok := jsonPieces(append(path, i), item)
+			if !ok {
+				return nil, false
+			}
+			pieces = append(pieces, itemPieces...)
+		}
+
+		return append(pieces, "]"), true
+	case map[string]interface{}:
+		keys := []string{}
+		for deepKey := range typed {
+			keys = append(keys, deepKey)
+		}
+		sort.Strings(keys)
+
+		pieces := []interface{}{"{"}
+		for i, deepKey := range keys {
+			if i > 0 {
+				pieces = append(pieces, ",")
+			}
+
+			itemPieces, ok := jsonPieces(append(path, deepKey), typed[deepKey])
+			if !ok {
+				return nil, false
+			}
+			pieces = append(pieces, encodeJsonString(deepKey)+":")
+			pieces = append(pieces, itemPieces...)
+		}
+
+		return append(pieces, "}"), true
+	}
+
+	if formatted, ok := formatScalar(value); ok {
+		return []interface{}{formatted}, true
+	}
+
+	panic(fmt.Errorf("Fn::ToJsonString at '%s' cannot serialize %#v", formatPath(path), value))
+}
+
+// joinPieces merges adjacent strings, returning either a single string,
+// or an Fn::Join of the strings and intrinsics.
+func joinPieces(pieces []interface{}) interface{} {
+	merged := []interface{}{}
+	for _, piece := range pieces {
+		if pieceString, ok := piece.(string); ok && len(merged) > 0 {
+			if lastString, ok := merged[len(merged)-1].(string); ok {
+				merged[len(merged)-1] = lastString + pieceString
+				continue
+			}
+		}
+
+		merged = append(merged, piece)
+	}
+
+	if len(merged) == 0 {
+		return interface{}("")
+	}
+
+	if len(merged) == 1 {
+		if mergedString, ok := merged[0].(string); ok {
+			return interface{}(mergedString)
+		}
+	}
+
+	return interface{}(map[string]interface{}{
+		"Fn::Join": []interface{}{"", merged},
+	})
+}
+
+func FnToJsonString(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::ToJsonString")
+	if !ok {
+		return key, node //passthru
+	}
+
+	pieces, ok := jsonPieces(path, argInterface)
+	if !ok {
+		return key, node //passthru (contains intrinsics which may yet be resolved)
+	}
+
+	return key, joinPieces(pieces)
+}
+
+func FnParseJson(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::ParseJson")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var argString string
+	if argString, ok = argInterface.(string); !ok {
+		return key, node //passthru
+	}
+
+	parsed := interface{}(nil)
+	if err := json.Unmarshal([]byte(argString), &parsed); err != nil {
+		panic(fmt.Errorf("Fn::ParseJson at '%s' could not parse JSON: %s", formatPath(path), err))
+	}
+
+	return key, parsed
+}
@@ -0,0 +1,168 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFnToJsonString_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnToJsonString, "Fn::ToJsonString", t)
+}
+
+func TestFnToJsonString_Basic(t *testing.T) {
+	inputs := []interface{}{
+		"a<b",
+		float64(1.5),
+		nil,
+		map[string]interface{}{
+			"b": []interface{}{true, float64(2)},
+			"a": "one",
+		},
+	}
+
+	expected := []interface{}{
+		"\"a<b\"",
+		"1.5",
+		"null",
+		"{\"a\":\"one\",\"b\":[true,2]}",
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::ToJsonString": input,
+		})
+
+		newKey, newNode := FnToJsonString([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnToJsonString modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnToJsonString of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnToJsonString_Intrinsics(t *testing.T) {
+	queueArn := map[string]interface{}{"Fn::GetAtt": []interface{}{"Queue", "Arn"}}
+	bucket := map[string]interface{}{"Ref": "Bucket"}
+
+	input := interface{}(map[string]interface{}{
+		"Fn::ToJsonString": map[string]interface{}{
+			"Resource": queueArn,
+			"Targets":  []interface{}{bucket, "literal"},
+		},
+	})
+
+	expected := map[string]interface{}{
+		"Fn::Join": []interface{}{"", []interface{}{
+			"{\"Resource\":\"",
+			queueArn,
+			"\",\"Targets\":[\"",
+			bucket,
+			"\",\"literal\"]}",
+		}},
+	}
+
+	newKey, newNode := FnToJsonString([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnToJsonString modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnToJsonString with intrinsics did not return the expected result (%#v instead of %#v)", newNode, expected)
+	}
+}
+
+func TestFnToJsonString_Passthru_Unresolved(t *testing.T) {
+	inputs := []interface{}{
+		map[string]interface{}{
+			"Fn::Add": []interface{}{map[string]interface{}{"Ref": "$i"}, float64(1)},
+		},
+		map[string]interface{}{
+			"Fn::If": []interface{}{"Condition", map[string]interface{}{"a": "b"}, map[string]interface{}{"Ref": "AWS::NoValue"}},
+		},
+		map[string]interface{}{
+			"Fn::Select": []interface{}{float64(0), map[string]interface{}{"Ref": "Subnets"}},
+		},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::ToJsonString": map[string]interface{}{"Value": input},
+		})
+
+		_, newNode := FnToJsonString([]interface{}{"x", "y"}, input)
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnToJsonString with an unresolved function modified the data (%#v instead of %#v)", newNode, input)
+		}
+	}
+}
+
+func TestFnToJsonString_Panic_ListIntrinsic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::ToJsonString": map[string]interface{}{
+			"Zones": map[string]interface{}{"Fn::GetAZs": ""},
+		},
+	})
+
+	_, _ = FnToJsonString([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnToJsonString of a list-valued intrinsic did not panic")
+}
+
+func TestFnParseJson_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnParseJson, "Fn::ParseJson", t)
+}
+
+func TestFnParseJson_Passthru_NonString(t *testing.T) {
+	input := interface{}(map[string]interface{}{
+		"Fn::ParseJson": map[string]interface{}{"Fn::Join": []interface{}{"", []interface{}{"{", "}"}}},
+	})
+
+	newKey, newNode := FnParseJson([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnParseJson modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, input) {
+		t.Fatalf("FnParseJson of a non-string modified the data (%v instead of %v)", newNode, input)
+	}
+}
+
+func TestFnParseJson_Panic_NonJSON(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::ParseJson": "nonJSON",
+	})
+
+	_, _ = FnParseJson([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnParseJson of a non-JSON string did not panic")
+}
+
+func TestFnParseJson_Basic(t *testing.T) {
+	original := map[string]interface{}{
+		"a": "one",
+		"b": []interface{}{true, float64(2), nil},
+	}
+
+	_, serialized := FnToJsonString([]interface{}{"x", "y"}, map[string]interface{}{"Fn::ToJsonString": original})
+	newKey, newNode := FnParseJson([]interface{}{"x", "y"}, map[string]interface{}{"Fn::ParseJson": serialized})
+	if newKey != "y" {
+		t.Fatalf("FnParseJson modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, interface{}(original)) {
+		t.Fatalf("FnParseJson of %v did not return the expected result (%#v instead of %#v)", serialized, newNode, original)
+	}
+}
@@ -0,0 +1,67 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fallbackmap"
+)
+
+func MakeFnLet(sources *deepstack.DeepStack, outerRules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		raw, ok := singleKey(node, "Fn::Let")
+		if !ok {
+			return key, node //passthru
+		}
+
+		// both arguments are left unprocessed, as each binding must be
+		// processed with the bindings before it in place.
+		args, ok := collectTemplateArgs(path, raw, 2, 2, outerRules)
+		if !ok {
+			return key, node //passthru
+		}
+
+		var bindings []interface{}
+		if bindings, ok = args[0].([]interface{}); !ok {
+			return key, node //passthru
+		}
+
+		for _, binding := range bindings {
+			var pair []interface{}
+			if pair, ok = binding.([]interface{}); !ok || len(pair) != 2 {
+				return key, node //passthru
+			}
+
+			if _, ok = pair[0].(string); !ok {
+				return key, node //passthru
+			}
+		}
+
+		pushed := 0
+		for _, binding := range bindings {
+			pair := binding.([]interface{})
+
+			newKey, value := template.Walk(path, pair[1], outerRules)
+			if skip, ok := newKey.(bool); ok && skip {
+				continue
+			}
+
+			sources.Push(fallbackmap.DeepMap(map[string]interface{}{
+				pair[0].(string): value,
+			}))
+			pushed++
+		}
+
+		innerTemplate := interface{}(args[1])
+		key, generated := template.Walk(path, innerTemplate, outerRules)
+		for ; pushed > 0; pushed-- {
+			sources.PopDiscard()
+		}
+
+		return key, interface{}(generated)
+	}
+}
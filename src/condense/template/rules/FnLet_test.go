@@ -0,0 +1,96 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fallbackmap"
+	"reflect"
+	"testing"
+)
+
+func testMakeFnLet(stack deepstack.DeepStack, rules template.Rules) template.Rule {
+	return MakeFnLet(&stack, &rules)
+}
+
+func TestFnLet_Passthru_NonMatching(t *testing.T) {
+	fnLet := testMakeFnLet(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonMatching(fnLet, "Fn::Let", t)
+}
+
+func TestFnLet_Passthru_NonArgsList(t *testing.T) {
+	fnLet := testMakeFnLet(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonArgsList(fnLet, "Fn::Let", t)
+}
+
+func TestFnLet_Passthru_BadArguments(t *testing.T) {
+	fnLet := testMakeFnLet(deepstack.DeepStack{}, template.Rules{})
+
+	inputs := [][]interface{}{
+		[]interface{}{[]interface{}{}},
+		[]interface{}{[]interface{}{}, "aTemplate", "tooMany"},
+		[]interface{}{"nonList", "aTemplate"},
+		[]interface{}{[]interface{}{"nonPair"}, "aTemplate"},
+		[]interface{}{[]interface{}{[]interface{}{"a", "one", "tooMany"}}, "aTemplate"},
+		[]interface{}{[]interface{}{[]interface{}{1, "one"}}, "aTemplate"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Let": input,
+		})
+
+		newKey, newNode := fnLet([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnLet modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnLet with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnLet_Sequential(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	stack.Push(fallbackmap.DeepMap(map[string]interface{}{"outer": "outerValue"}))
+
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnJoin)
+
+	fnLet := MakeFnLet(&stack, &templateRules)
+
+	join := func(pieces ...interface{}) interface{} {
+		return map[string]interface{}{"Fn::Join": []interface{}{"-", pieces}}
+	}
+
+	input := interface{}(map[string]interface{}{
+		"Fn::Let": []interface{}{
+			[]interface{}{
+				[]interface{}{"$env", "prod"},
+				[]interface{}{"$prefix", join(map[string]interface{}{"Ref": "outer"}, map[string]interface{}{"Ref": "$env"})},
+				[]interface{}{"$name", join(map[string]interface{}{"Ref": "$prefix"}, "queue")},
+			},
+			map[string]interface{}{"QueueName": map[string]interface{}{"Ref": "$name"}},
+		},
+	})
+
+	expected := map[string]interface{}{"QueueName": "outerValue-prod-queue"}
+
+	newKey, newNode := fnLet([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnLet modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnLet of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected)
+	}
+
+	if _, ok := stack.Get([]string{"$env"}); ok {
+		t.Fatalf("FnLet did not remove its bindings from the stack")
+	}
+
+	if _, ok := stack.Get([]string{"outer"}); !ok {
+		t.Fatalf("FnLet removed too many bindings from the stack")
+	}
+}
@@ -0,0 +1,239 @@
+package rules
+
+func listOfLists(argsInterface interface{}) ([][]interface{}, bool) {
+	var args []interface{}
+	var ok bool
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return nil, false
+	}
+
+	lists := [][]interface{}{}
+	for _, argInterface := range args {
+		var argArray []interface{}
+		if argArray, ok = argInterface.([]interface{}); !ok {
+			return nil, false
+		}
+
+		lists = append(lists, argArray)
+	}
+
+	return lists, true
+}
+
+func sliceIndex(index float64, length int) int {
+	i := int(index)
+	if i < 0 {
+		i += length
+	}
+
+	if i < 0 {
+		return 0
+	}
+
+	if i > length {
+		return length
+	}
+
+	return i
+}
+
+func FnSlice(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Slice")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	if len(args) != 2 && len(args) != 3 {
+		return key, node //passthru
+	}
+
+	var values []interface{}
+	if values, ok = args[0].([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	var startFloat float64
+	if startFloat, ok = args[1].(float64); !ok {
+		return key, node //passthru
+	}
+
+	endFloat := float64(len(values))
+	if len(args) == 3 {
+		if endFloat, ok = args[2].(float64); !ok {
+			return key, node //passthru
+		}
+	}
+
+	start := sliceIndex(startFloat, len(values))
+	end := sliceIndex(endFloat, len(values))
+
+	sliced := []interface{}{}
+	if start < end {
+		sliced = append(sliced, values[start:end]...)
+	}
+
+	return key, interface{}(sliced)
+}
+
+func FnReverse(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Reverse")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	reversed := []interface{}{}
+	for i := len(args) - 1; i >= 0; i-- {
+		reversed = append(reversed, args[i])
+	}
+
+	return key, interface{}(reversed)
+}
+
+func flatten(depth int, values []interface{}) []interface{} {
+	flattened := []interface{}{}
+	for _, value := range values {
+		if valueArray, ok := value.([]interface{}); ok && depth > 0 {
+			flattened = append(flattened, flatten(depth-1, valueArray)...)
+			continue
+		}
+
+		flattened = append(flattened, value)
+	}
+
+	return flattened
+}
+
+func FnFlatten(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Flatten")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	if len(args) != 2 {
+		return key, node //passthru
+	}
+
+	var depthFloat float64
+	if depthFloat, ok = args[0].(float64); !ok {
+		return key, node //passthru
+	}
+
+	var values []interface{}
+	if values, ok = args[1].([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	return key, interface{}(flatten(int(depthFloat), values))
+}
+
+func FnZip(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Zip")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var lists [][]interface{}
+	if lists, ok = listOfLists(argsInterface); !ok {
+		return key, node //can't zip non-arrays, passthru
+	}
+
+	zipped := []interface{}{}
+	if len(lists) == 0 {
+		return key, interface{}(zipped)
+	}
+
+	shortest := len(lists[0])
+	for _, list := range lists[1:] {
+		if len(list) < shortest {
+			shortest = len(list)
+		}
+	}
+
+	for i := 0; i < shortest; i++ {
+		tuple := []interface{}{}
+		for _, list := range lists {
+			tuple = append(tuple, list[i])
+		}
+
+		zipped = append(zipped, interface{}(tuple))
+	}
+
+	return key, interface{}(zipped)
+}
+
+func FnProduct(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Product")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var lists [][]interface{}
+	if lists, ok = listOfLists(argsInterface); !ok {
+		return key, node //can't multiply non-arrays, passthru
+	}
+
+	if len(lists) == 0 {
+		return key, interface{}([]interface{}{})
+	}
+
+	tuples := [][]interface{}{[]interface{}{}}
+	for _, list := range lists {
+		next := [][]interface{}{}
+		for _, tuple := range tuples {
+			for _, item := range list {
+				extended := make([]interface{}, len(tuple), len(tuple)+1)
+				copy(extended, tuple)
+				next = append(next, append(extended, item))
+			}
+		}
+
+		tuples = next
+	}
+
+	product := []interface{}{}
+	for _, tuple := range tuples {
+		product = append(product, interface{}(tuple))
+	}
+
+	return key, interface{}(product)
+}
@@ -0,0 +1,144 @@
+package rules
+
+import (
+	"condense/template"
+	"reflect"
+	"testing"
+)
+
+func TestFnLists_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnSlice, "Fn::Slice", t)
+	testRule_Passthru_NonMatching(FnReverse, "Fn::Reverse", t)
+	testRule_Passthru_NonMatching(FnFlatten, "Fn::Flatten", t)
+	testRule_Passthru_NonMatching(FnZip, "Fn::Zip", t)
+	testRule_Passthru_NonMatching(FnProduct, "Fn::Product", t)
+}
+
+func TestFnLists_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnSlice, "Fn::Slice", t)
+	testRule_Passthru_NonArgsList(FnReverse, "Fn::Reverse", t)
+	testRule_Passthru_NonArgsList(FnFlatten, "Fn::Flatten", t)
+	testRule_Passthru_NonArgsList(FnZip, "Fn::Zip", t)
+	testRule_Passthru_NonArgsList(FnProduct, "Fn::Product", t)
+}
+
+func TestFnLists_Passthru_Unresolved(t *testing.T) {
+	unresolved := map[string]interface{}{"Ref": "Unresolved"}
+
+	rules := map[string]template.Rule{
+		"Fn::Slice":   FnSlice,
+		"Fn::Flatten": FnFlatten,
+		"Fn::Zip":     FnZip,
+		"Fn::Product": FnProduct,
+	}
+
+	inputs := map[string][]interface{}{
+		"Fn::Slice": []interface{}{
+			[]interface{}{unresolved, float64(0)},
+			[]interface{}{[]interface{}{"a"}, unresolved},
+			[]interface{}{[]interface{}{"a"}},
+			[]interface{}{[]interface{}{"a"}, float64(0), float64(1), "tooMany"},
+		},
+		"Fn::Flatten": []interface{}{
+			[]interface{}{float64(1), unresolved},
+			[]interface{}{unresolved, []interface{}{}},
+		},
+		"Fn::Zip": []interface{}{
+			[]interface{}{[]interface{}{"a"}, unresolved},
+		},
+		"Fn::Product": []interface{}{
+			[]interface{}{unresolved, []interface{}{"a"}},
+		},
+	}
+
+	for fnName, fnInputs := range inputs {
+		for _, input := range fnInputs {
+			input := interface{}(map[string]interface{}{
+				fnName: input,
+			})
+
+			newKey, newNode := rules[fnName]([]interface{}{"x", "y"}, input)
+			if newKey != "y" {
+				t.Fatalf("%s modified the path (%v instead of %v)", fnName, newKey, "y")
+			}
+
+			if !reflect.DeepEqual(newNode, input) {
+				t.Fatalf("%s with unresolved arguments %v modified the data (%v instead of %v)", fnName, input, newNode, input)
+			}
+		}
+	}
+}
+
+func TestFnLists_Basic(t *testing.T) {
+	azs := []interface{}{"a", "b", "c"}
+
+	rules := []template.Rule{
+		FnSlice,
+		FnSlice,
+		FnSlice,
+		FnReverse,
+		FnFlatten,
+		FnFlatten,
+		FnZip,
+		FnProduct,
+		FnProduct,
+	}
+
+	inputs := []interface{}{
+		map[string]interface{}{"Fn::Slice": []interface{}{azs, float64(0), float64(2)}},
+		map[string]interface{}{"Fn::Slice": []interface{}{azs, float64(-1)}},
+		map[string]interface{}{"Fn::Slice": []interface{}{azs, float64(2), float64(10)}},
+		map[string]interface{}{"Fn::Reverse": azs},
+		map[string]interface{}{"Fn::Flatten": []interface{}{float64(1), []interface{}{
+			[]interface{}{"a", []interface{}{"b"}},
+			"c",
+		}}},
+		map[string]interface{}{"Fn::Flatten": []interface{}{float64(2), []interface{}{
+			[]interface{}{"a", []interface{}{"b"}},
+			"c",
+		}}},
+		map[string]interface{}{"Fn::Zip": []interface{}{
+			[]interface{}{"a", "b", "c"},
+			[]interface{}{float64(1), float64(2)},
+		}},
+		map[string]interface{}{"Fn::Product": []interface{}{
+			[]interface{}{float64(80), float64(443)},
+			[]interface{}{"10.0.0.0/8", "192.168.0.0/16"},
+		}},
+		map[string]interface{}{"Fn::Product": []interface{}{
+			[]interface{}{float64(80)},
+			[]interface{}{},
+		}},
+	}
+
+	expected := []interface{}{
+		[]interface{}{"a", "b"},
+		[]interface{}{"c"},
+		[]interface{}{"c"},
+		[]interface{}{"c", "b", "a"},
+		[]interface{}{"a", []interface{}{"b"}, "c"},
+		[]interface{}{"a", "b", "c"},
+		[]interface{}{
+			[]interface{}{"a", float64(1)},
+			[]interface{}{"b", float64(2)},
+		},
+		[]interface{}{
+			[]interface{}{float64(80), "10.0.0.0/8"},
+			[]interface{}{float64(80), "192.168.0.0/16"},
+			[]interface{}{float64(443), "10.0.0.0/8"},
+			[]interface{}{float64(443), "192.168.0.0/16"},
+		},
+		[]interface{}{},
+	}
+
+	for i, input := range inputs {
+		newKey, newNode := rules[i]([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("List function modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("List function of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package rules
+
+import (
+	"deepalias"
+	"fmt"
+	"strconv"
+)
+
+func objectAndKeys(node interface{}, fnName string) (map[string]interface{}, []string, bool) {
+	argsInterface, ok := singleKey(node, fnName)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return nil, nil, false
+	}
+
+	if len(args) != 2 {
+		return nil, nil, false
+	}
+
+	var argMap map[string]interface{}
+	if argMap, ok = args[0].(map[string]interface{}); !ok || isIntrinsic(argMap) {
+		return nil, nil, false
+	}
+
+	var keysInterface []interface{}
+	if keysInterface, ok = args[1].([]interface{}); !ok {
+		return nil, nil, false
+	}
+
+	keys := []string{}
+	for _, keyInterface := range keysInterface {
+		var keyString string
+		if keyString, ok = keyInterface.(string); !ok {
+			return nil, nil, false
+		}
+
+		keys = append(keys, keyString)
+	}
+
+	return argMap, keys, true
+}
+
+func FnPick(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argMap, keys, ok := objectAndKeys(node, "Fn::Pick")
+	if !ok {
+		return key, node //passthru
+	}
+
+	picked := make(map[string]interface{})
+	for _, deepKey := range keys {
+		if value, ok := argMap[deepKey]; ok {
+			picked[deepKey] = value
+		}
+	}
+
+	return key, interface{}(picked)
+}
+
+func FnOmit(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argMap, keys, ok := objectAndKeys(node, "Fn::Omit")
+	if !ok {
+		return key, node //passthru
+	}
+
+	omitted := make(map[string]interface{})
+	for deepKey, value := range argMap {
+		omitted[deepKey] = value
+	}
+
+	for _, deepKey := range keys {
+		delete(omitted, deepKey)
+	}
+
+	return key, interface{}(omitted)
+}
+
+func FnRenameKeys(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::RenameKeys")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	if len(args) != 2 {
+		return key, node //passthru
+	}
+
+	var argMap map[string]interface{}
+	if argMap, ok = args[0].(map[string]interface{}); !ok || isIntrinsic(argMap) {
+		return key, node //passthru
+	}
+
+	var renames map[string]interface{}
+	if renames, ok = args[1].(map[string]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	for _, newName := range renames {
+		if _, ok = newName.(string); !ok {
+			return key, node //passthru
+		}
+	}
+
+	renamed := make(map[string]interface{})
+	for deepKey, value := range argMap {
+		if newName, ok := renames[deepKey]; ok {
+			deepKey = newName.(string)
+		}
+
+		renamed[deepKey] = value
+	}
+
+	return key, interface{}(renamed)
+}
+
+// getPath follows a path through nested objects and arrays, where array
+// elements are addressed by their numeric index. unresolved reports that
+// the path ran into an intrinsic, which may yet resolve to contain it.
+func getPath(value interface{}, parts []string) (result interface{}, ok bool, unresolved bool) {
+	for _, part := range parts {
+		if isIntrinsic(value) {
+			return nil, false, true
+		}
+
+		switch typed := value.(type) {
+		default:
+			return nil, false, false
+		case map[string]interface{}:
+			if value, ok = typed[part]; !ok {
+				return nil, false, false
+			}
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, false, false
+			}
+
+			value = typed[index]
+		}
+	}
+
+	return value, true, false
+}
+
+func FnGet(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Get")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	if len(args) != 2 && len(args) != 3 {
+		return key, node //passthru
+	}
+
+	switch args[0].(type) {
+	default:
+		return key, node //passthru
+	case map[string]interface{}:
+	case []interface{}:
+	}
+
+	var pathString string
+	if pathString, ok = args[1].(string); !ok {
+		return key, node //passthru
+	}
+
+	value, ok, unresolved := getPath(args[0], deepalias.Split(pathString))
+	if unresolved {
+		return key, node //can't look into unresolved values, passthru
+	}
+
+	if ok {
+		return key, value
+	}
+
+	if len(args) == 3 {
+		return key, args[2]
+	}
+
+	panic(fmt.Errorf("Fn::Get at '%s' could not find '%s', and no default was given", formatPath(path), pathString))
+}
@@ -0,0 +1,169 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"reflect"
+	"testing"
+)
+
+func TestFnObjects_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnPick, "Fn::Pick", t)
+	testRule_Passthru_NonMatching(FnOmit, "Fn::Omit", t)
+	testRule_Passthru_NonMatching(FnRenameKeys, "Fn::RenameKeys", t)
+	testRule_Passthru_NonMatching(FnGet, "Fn::Get", t)
+}
+
+func TestFnObjects_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnPick, "Fn::Pick", t)
+	testRule_Passthru_NonArgsList(FnOmit, "Fn::Omit", t)
+	testRule_Passthru_NonArgsList(FnRenameKeys, "Fn::RenameKeys", t)
+	testRule_Passthru_NonArgsList(FnGet, "Fn::Get", t)
+}
+
+func TestFnObjects_Passthru_BadArguments(t *testing.T) {
+	unresolved := map[string]interface{}{"Ref": "Unresolved"}
+	object := map[string]interface{}{"a": "one"}
+
+	rules := map[string]template.Rule{
+		"Fn::Pick":       FnPick,
+		"Fn::Omit":       FnOmit,
+		"Fn::RenameKeys": FnRenameKeys,
+		"Fn::Get":        FnGet,
+	}
+
+	inputs := map[string][]interface{}{
+		"Fn::Pick": []interface{}{
+			[]interface{}{object},
+			[]interface{}{"nonObject", []interface{}{"a"}},
+			[]interface{}{object, []interface{}{1}},
+		},
+		"Fn::Omit": []interface{}{
+			[]interface{}{object, "nonList"},
+		},
+		"Fn::RenameKeys": []interface{}{
+			[]interface{}{object, map[string]interface{}{"a": 1}},
+			[]interface{}{object, "nonObject"},
+		},
+		"Fn::Get": []interface{}{
+			[]interface{}{object},
+			[]interface{}{"nonObject", "a", "default"},
+			[]interface{}{object, 1, "default"},
+			[]interface{}{unresolved, "a", "default"},
+			[]interface{}{map[string]interface{}{"cfg": unresolved}, "cfg.port"},
+			[]interface{}{[]interface{}{unresolved}, "0.port", "default"},
+		},
+	}
+
+	for fnName, fnInputs := range inputs {
+		for _, input := range fnInputs {
+			input := interface{}(map[string]interface{}{
+				fnName: input,
+			})
+
+			newKey, newNode := rules[fnName]([]interface{}{"x", "y"}, input)
+			if newKey != "y" {
+				t.Fatalf("%s modified the path (%v instead of %v)", fnName, newKey, "y")
+			}
+
+			if !reflect.DeepEqual(newNode, input) {
+				t.Fatalf("%s with bad arguments %v modified the data (%v instead of %v)", fnName, input, newNode, input)
+			}
+		}
+	}
+}
+
+func TestFnGet_Panic_MissingWithoutDefault(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::Get": []interface{}{map[string]interface{}{"a": "one"}, "b"},
+	})
+
+	_, _ = FnGet([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnGet of a missing path without a default did not panic")
+}
+
+func TestFnGet_InFor(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(MakeFnFor(&stack, &templateRules))
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnGet)
+
+	input := interface{}(map[string]interface{}{
+		"Fn::For": []interface{}{
+			[]interface{}{"$v"},
+			[]interface{}{
+				map[string]interface{}{"port": float64(80)},
+				map[string]interface{}{"port": float64(443)},
+			},
+			map[string]interface{}{"Fn::Get": []interface{}{
+				map[string]interface{}{"cfg": map[string]interface{}{"Ref": "$v"}},
+				"cfg.port",
+			}},
+		},
+	})
+
+	expected := []interface{}{float64(80), float64(443)}
+
+	_, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnGet within Fn::For did not return the expected result (%#v instead of %#v)", newNode, expected)
+	}
+}
+
+func TestFnObjects_Basic(t *testing.T) {
+	object := map[string]interface{}{
+		"a": "one",
+		"b": "two",
+		"c": map[string]interface{}{
+			"list": []interface{}{"x", map[string]interface{}{"deep": "y"}},
+		},
+	}
+
+	rules := []template.Rule{
+		FnPick,
+		FnOmit,
+		FnRenameKeys,
+		FnGet,
+		FnGet,
+		FnGet,
+		FnGet,
+	}
+
+	inputs := []interface{}{
+		map[string]interface{}{"Fn::Pick": []interface{}{object, []interface{}{"a", "missing"}}},
+		map[string]interface{}{"Fn::Omit": []interface{}{object, []interface{}{"c", "missing"}}},
+		map[string]interface{}{"Fn::RenameKeys": []interface{}{object, map[string]interface{}{"a": "A", "missing": "M"}}},
+		map[string]interface{}{"Fn::Get": []interface{}{object, "c.list.1.deep", "default"}},
+		map[string]interface{}{"Fn::Get": []interface{}{object, "c.list.5", "default"}},
+		map[string]interface{}{"Fn::Get": []interface{}{object, "a"}},
+		map[string]interface{}{"Fn::Get": []interface{}{[]interface{}{"x", "y"}, "1"}},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"a": "one"},
+		map[string]interface{}{"a": "one", "b": "two"},
+		map[string]interface{}{"A": "one", "b": "two", "c": object["c"]},
+		"y",
+		"default",
+		"one",
+		"y",
+	}
+
+	for i, input := range inputs {
+		newKey, newNode := rules[i]([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("Object function modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("Object function of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxRangeLength is the maximum number of values generated by Fn::Range.
+const MaxRangeLength = 10000
+
+func FnRange(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := numericArgs(node, "Fn::Range")
+	if !ok {
+		return key, node //passthru
+	}
+
+	start, end, step := float64(0), float64(0), float64(1)
+	switch len(args) {
+	default:
+		return key, node //passthru
+	case 1:
+		end = args[0]
+	case 2:
+		start, end = args[0], args[1]
+	case 3:
+		start, end, step = args[0], args[1], args[2]
+	}
+
+	if step == 0 {
+		panic(fmt.Errorf("Zero step in Fn::Range at '%s'", formatPath(path)))
+	}
+
+	count := math.Ceil((end - start) / step)
+	if math.IsNaN(count) || math.IsInf(count, 0) || count > MaxRangeLength {
+		panic(fmt.Errorf("Fn::Range at '%s' from %v to %v by %v exceeds the maximum length of %d", formatPath(path), start, end, step, MaxRangeLength))
+	}
+
+	generated := []interface{}{}
+	for i := float64(0); i < count; i++ {
+		value := start + i*step
+		if (step > 0 && value >= end) || (step < 0 && value <= end) {
+			break // rounding may overshoot the end by one step
+		}
+
+		generated = append(generated, interface{}(value))
+	}
+
+	return key, interface{}(generated)
+}
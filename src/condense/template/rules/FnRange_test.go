@@ -0,0 +1,115 @@
+package rules
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFnRange_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnRange, "Fn::Range", t)
+}
+
+func TestFnRange_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnRange, "Fn::Range", t)
+}
+
+func TestFnRange_Passthru_BadArguments(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{},
+		[]interface{}{float64(0), float64(4), float64(1), float64(1)},
+		[]interface{}{float64(0), map[string]interface{}{"Ref": "SubnetCount"}},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Range": input,
+		})
+
+		newKey, newNode := FnRange([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnRange modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnRange with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnRange_Panic_ZeroStep(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::Range": []interface{}{float64(0), float64(4), float64(0)},
+	})
+
+	_, _ = FnRange([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnRange with a zero step did not panic")
+}
+
+func TestFnRange_Panic_TooLong(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{float64(1), float64(2), float64(1e-20)},
+		[]interface{}{float64(1e300)},
+		[]interface{}{math.Inf(1)},
+		[]interface{}{float64(0), math.NaN()},
+	}
+
+	for _, input := range inputs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					// do nothing
+				}
+			}()
+
+			_, _ = FnRange([]interface{}{"x", "y"}, interface{}(map[string]interface{}{
+				"Fn::Range": input,
+			}))
+			t.Fatalf("FnRange of %v did not panic", input)
+		}()
+	}
+}
+
+func TestFnRange_Basic(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{float64(3)},
+		[]interface{}{float64(2), float64(5)},
+		[]interface{}{float64(0), float64(10), float64(4)},
+		[]interface{}{float64(3), float64(0), float64(-1)},
+		[]interface{}{float64(3), float64(0)},
+		[]interface{}{float64(0), float64(1), float64(0.1)},
+	}
+
+	expected := []interface{}{
+		[]interface{}{float64(0), float64(1), float64(2)},
+		[]interface{}{float64(2), float64(3), float64(4)},
+		[]interface{}{float64(0), float64(4), float64(8)},
+		[]interface{}{float64(3), float64(2), float64(1)},
+		[]interface{}{},
+		[]interface{}{
+			float64(0), float64(0.1), float64(0.2), float64(0.30000000000000004), float64(0.4),
+			float64(0.5), float64(0.6000000000000001), float64(0.7000000000000001), float64(0.8), float64(0.9),
+		},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Range": input,
+		})
+
+		newKey, newNode := FnRange([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnRange modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnRange of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
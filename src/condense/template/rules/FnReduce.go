@@ -0,0 +1,87 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+)
+
+// parseReduceNames interprets the binding names of Fn::Reduce: a list of
+// [accumulator, value] or [accumulator, index, value] names, where any may
+// be null to leave it unbound.
+func parseReduceNames(arg interface{}) ([]interface{}, bool) {
+	var names []interface{}
+	var ok bool
+
+	if names, ok = arg.([]interface{}); !ok {
+		return nil, false
+	}
+
+	if len(names) == 2 {
+		names = []interface{}{names[0], nil, names[1]}
+	} else if len(names) != 3 {
+		return nil, false
+	}
+
+	for _, name := range names {
+		if _, ok = name.(string); !ok && name != nil {
+			return nil, false
+		}
+	}
+
+	return names, true
+}
+
+func MakeFnReduce(sources *deepstack.DeepStack, templateRules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		raw, ok := singleKey(node, "Fn::Reduce")
+		if !ok {
+			return key, node //passthru
+		}
+
+		args, ok := collectTemplateArgs(path, raw, 4, 1, templateRules)
+		if !ok {
+			return key, node //passthru
+		}
+
+		var names []interface{}
+		if names, ok = parseReduceNames(args[0]); !ok {
+			return key, node //passthru
+		}
+
+		var values []interface{}
+		if values, ok = args[2].([]interface{}); !ok {
+			return key, node //passthru
+		}
+
+		accumulator := args[1]
+		stepTemplate := interface{}(args[3])
+
+		for deepIndex, value := range values {
+			deepPath := make([]interface{}, len(path)+1)
+			copy(deepPath, path)
+			deepPath[cap(deepPath)-1] = interface{}(deepIndex)
+
+			bindings := bindRefNames(names[1:], float64(deepIndex), value)
+			if names[0] != nil {
+				bindings[names[0].(string)] = accumulator
+			}
+			sources.Push(bindings)
+
+			newIndex, processed := template.Walk(deepPath, stepTemplate, templateRules)
+			sources.PopDiscard()
+
+			if skip, ok := newIndex.(bool); ok && skip {
+				continue
+			}
+
+			accumulator = processed
+		}
+
+		return key, accumulator
+	}
+}
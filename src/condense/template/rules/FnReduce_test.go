@@ -0,0 +1,149 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"reflect"
+	"testing"
+)
+
+func testMakeFnReduce(stack deepstack.DeepStack, rules template.Rules) template.Rule {
+	return MakeFnReduce(&stack, &rules)
+}
+
+func TestFnReduce_Passthru_NonMatching(t *testing.T) {
+	fnReduce := testMakeFnReduce(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonMatching(fnReduce, "Fn::Reduce", t)
+}
+
+func TestFnReduce_Passthru_NonArgsList(t *testing.T) {
+	fnReduce := testMakeFnReduce(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonArgsList(fnReduce, "Fn::Reduce", t)
+}
+
+func TestFnReduce_Passthru_BadArguments(t *testing.T) {
+	fnReduce := testMakeFnReduce(deepstack.DeepStack{}, template.Rules{})
+
+	names := []interface{}{"$acc", "$value"}
+	inputs := [][]interface{}{
+		[]interface{}{names, float64(0), []interface{}{1, 2}},
+		[]interface{}{names, float64(0), []interface{}{1, 2}, "aTemplate", "tooMany"},
+		[]interface{}{names, float64(0), "nonList", "aTemplate"},
+		[]interface{}{"$acc", float64(0), []interface{}{1, 2}, "aTemplate"},
+		[]interface{}{[]interface{}{"$acc"}, float64(0), []interface{}{1, 2}, "aTemplate"},
+		[]interface{}{[]interface{}{"$acc", float64(1), "$value"}, float64(0), []interface{}{1, 2}, "aTemplate"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Reduce": input,
+		})
+
+		newKey, newNode := fnReduce([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnReduce modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnReduce with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnReduce_EmptyValues(t *testing.T) {
+	fnReduce := testMakeFnReduce(deepstack.DeepStack{}, template.Rules{})
+
+	input := interface{}(map[string]interface{}{
+		"Fn::Reduce": []interface{}{
+			[]interface{}{"$acc", "$value"}, "initial", []interface{}{}, "aTemplate",
+		},
+	})
+
+	expected := interface{}("initial")
+	newKey, newNode := fnReduce([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnReduce modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnReduce with empty values did not return the initial value (%#v instead of %#v)", newNode, expected)
+	}
+}
+
+func TestFnReduce_Basic(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnAdd)
+	templateRules.Attach(FnMerge)
+	templateRules.Attach(FnConcat)
+	templateRules.Attach(FnJoin)
+
+	fnReduce := MakeFnReduce(&stack, &templateRules)
+
+	inputs := [][]interface{}{
+		[]interface{}{
+			[]interface{}{"$acc", "$value"},
+			float64(0),
+			[]interface{}{float64(10), float64(20), float64(30)},
+			map[string]interface{}{"Fn::Add": []interface{}{
+				map[string]interface{}{"Ref": "$acc"},
+				map[string]interface{}{"Ref": "$value"},
+			}},
+		},
+		[]interface{}{
+			[]interface{}{"$acc", nil, "$value"},
+			map[string]interface{}{},
+			[]interface{}{
+				map[string]interface{}{"a": "one"},
+				map[string]interface{}{"b": "two"},
+				map[string]interface{}{"a": "three"},
+			},
+			map[string]interface{}{"Fn::Merge": []interface{}{
+				map[string]interface{}{"Ref": "$acc"},
+				map[string]interface{}{"Ref": "$value"},
+			}},
+		},
+		[]interface{}{
+			[]interface{}{"$acc", "$i", "$value"},
+			[]interface{}{},
+			[]interface{}{"a", "b"},
+			map[string]interface{}{"Fn::Concat": []interface{}{
+				map[string]interface{}{"Ref": "$acc"},
+				[]interface{}{map[string]interface{}{"Ref": "$i"}},
+			}},
+		},
+		[]interface{}{
+			[]interface{}{"total", "n", "item"},
+			"",
+			[]interface{}{"a", "b"},
+			map[string]interface{}{"Fn::Join": []interface{}{"", []interface{}{
+				map[string]interface{}{"Ref": "total"},
+				map[string]interface{}{"Ref": "item"},
+				map[string]interface{}{"Ref": "n"},
+			}}},
+		},
+	}
+
+	expected := []interface{}{
+		float64(60),
+		map[string]interface{}{"a": "three", "b": "two"},
+		[]interface{}{float64(0), float64(1)},
+		"a0b1",
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Reduce": input,
+		})
+
+		newKey, newNode := fnReduce([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnReduce modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnReduce of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+)
+
+func compileRegex(path []interface{}, fnName string, pattern string) *regexp.Regexp {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Errorf("Invalid pattern '%s' in %s at '%s': %s", pattern, fnName, formatPath(path), err))
+	}
+
+	return re
+}
+
+func regexArgs(node interface{}, fnName string, count int) ([]string, bool) {
+	argsInterface, ok := singleKey(node, fnName)
+	if !ok {
+		return nil, false
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return nil, false
+	}
+
+	if len(args) != count {
+		return nil, false
+	}
+
+	argStrings := []string{}
+	for _, arg := range args {
+		var argString string
+		if argString, ok = arg.(string); !ok {
+			return nil, false
+		}
+
+		argStrings = append(argStrings, argString)
+	}
+
+	return argStrings, true
+}
+
+func FnRegexMatch(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := regexArgs(node, "Fn::RegexMatch", 2)
+	if !ok {
+		return key, node //passthru
+	}
+
+	re := compileRegex(path, "Fn::RegexMatch", args[0])
+	return key, interface{}(re.MatchString(args[1]))
+}
+
+func FnRegexCapture(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := regexArgs(node, "Fn::RegexCapture", 2)
+	if !ok {
+		return key, node //passthru
+	}
+
+	re := compileRegex(path, "Fn::RegexCapture", args[0])
+
+	captured := make(map[string]interface{})
+	matches := re.FindStringSubmatch(args[1])
+	if matches == nil {
+		return key, interface{}(captured)
+	}
+
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		captured[name] = matches[i]
+	}
+
+	return key, interface{}(captured)
+}
+
+func FnRegexReplace(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	args, ok := regexArgs(node, "Fn::RegexReplace", 3)
+	if !ok {
+		return key, node //passthru
+	}
+
+	re := compileRegex(path, "Fn::RegexReplace", args[0])
+	return key, interface{}(re.ReplaceAllString(args[2], args[1]))
+}
@@ -0,0 +1,202 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFnRegexMatch_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnRegexMatch, "Fn::RegexMatch", t)
+}
+
+func TestFnRegexMatch_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnRegexMatch, "Fn::RegexMatch", t)
+}
+
+func TestFnRegexMatch_Passthru_WrongNumberOfArguments(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{"^a"},
+		[]interface{}{"^a", "abc", "tooMany"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::RegexMatch": input,
+		})
+
+		newKey, newNode := FnRegexMatch([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnRegexMatch modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnRegexMatch of wrong-sized args-list %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnRegexMatch_Passthru_NonString(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{1.0, "abc"},
+		[]interface{}{"^a", map[string]interface{}{"Ref": "Unresolved"}},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::RegexMatch": input,
+		})
+
+		newKey, newNode := FnRegexMatch([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnRegexMatch modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnRegexMatch with non-string arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnRegexMatch_Panic_BadPattern(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(error); !ok {
+				t.Fatalf("FnRegexMatch with an invalid pattern did not panic with an error (%#v)", r)
+			}
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::RegexMatch": []interface{}{"(unclosed", "abc"},
+	})
+
+	_, _ = FnRegexMatch([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnRegexMatch with an invalid pattern did not panic")
+}
+
+func TestFnRegexMatch_Basic(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{"^[a-z]+$", "abc"},
+		[]interface{}{"^[a-z]+$", "aBc"},
+		[]interface{}{"b", "abc"},
+	}
+
+	expected := []interface{}{
+		true,
+		false,
+		true,
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::RegexMatch": input,
+		})
+
+		newKey, newNode := FnRegexMatch([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnRegexMatch modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnRegexMatch of %v did not return the expected result (%v instead of %v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnRegexCapture_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnRegexCapture, "Fn::RegexCapture", t)
+}
+
+func TestFnRegexCapture_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnRegexCapture, "Fn::RegexCapture", t)
+}
+
+func TestFnRegexCapture_Basic(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{
+			"^arn:aws:(?P<service>[^:]+):(?P<region>[^:]*):(?P<account>[^:]*):(?P<resource>.*)$",
+			"arn:aws:sqs:eu-west-1:123456789012:aQueue",
+		},
+		[]interface{}{"^(?P<first>a)(?P<second>x)?", "abc"},
+		[]interface{}{"^(?P<first>a)", "nonMatching"},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"service":  "sqs",
+			"region":   "eu-west-1",
+			"account":  "123456789012",
+			"resource": "aQueue",
+		},
+		map[string]interface{}{
+			"first":  "a",
+			"second": "",
+		},
+		map[string]interface{}{},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::RegexCapture": input,
+		})
+
+		newKey, newNode := FnRegexCapture([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnRegexCapture modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnRegexCapture of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnRegexReplace_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnRegexReplace, "Fn::RegexReplace", t)
+}
+
+func TestFnRegexReplace_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnRegexReplace, "Fn::RegexReplace", t)
+}
+
+func TestFnRegexReplace_Panic_BadPattern(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	input := interface{}(map[string]interface{}{
+		"Fn::RegexReplace": []interface{}{"[", "x", "abc"},
+	})
+
+	_, _ = FnRegexReplace([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnRegexReplace with an invalid pattern did not panic")
+}
+
+func TestFnRegexReplace_Basic(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{"[^a-zA-Z0-9]", "", "a-stack_name.1"},
+		[]interface{}{"^(\\w+)-(\\w+)$", "${2}-${1}", "one-two"},
+	}
+
+	expected := []interface{}{
+		"astackname1",
+		"two-one",
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::RegexReplace": input,
+		})
+
+		newKey, newNode := FnRegexReplace([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnRegexReplace modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnRegexReplace of %v did not return the expected result (%v instead of %v)", input, newNode, expected[i])
+		}
+	}
+}
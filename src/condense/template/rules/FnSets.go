@@ -0,0 +1,180 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// hashValue returns a canonical representation of a value, such that
+// equivalent values (including 1 and 1.0, or objects with the same entries)
+// share the same hash.
+func hashValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Errorf("Unable to compare value %#v: %s", value, err))
+	}
+
+	return string(encoded)
+}
+
+type valueSet map[string]bool
+
+func newValueSet(values []interface{}) valueSet {
+	set := valueSet{}
+	for _, value := range values {
+		set[hashValue(value)] = true
+	}
+
+	return set
+}
+
+func (set valueSet) contains(value interface{}) bool {
+	return set[hashValue(value)]
+}
+
+// add adds a value to the set, returning false if it was already present
+func (set valueSet) add(value interface{}) bool {
+	hash := hashValue(value)
+	if set[hash] {
+		return false
+	}
+
+	set[hash] = true
+	return true
+}
+
+func unique(values []interface{}) []interface{} {
+	seen := valueSet{}
+	filtered := []interface{}{}
+	for _, value := range values {
+		if seen.add(value) {
+			filtered = append(filtered, value)
+		}
+	}
+
+	return filtered
+}
+
+func FnContains(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Contains")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	if len(args) != 2 {
+		return key, node //passthru
+	}
+
+	var haystack []interface{}
+	if haystack, ok = args[0].([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	return key, interface{}(newValueSet(haystack).contains(args[1]))
+}
+
+func FnUnion(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Union")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var lists [][]interface{}
+	if lists, ok = listOfLists(argsInterface); !ok {
+		return key, node //can't combine non-arrays, passthru
+	}
+
+	combined := []interface{}{}
+	for _, list := range lists {
+		combined = append(combined, list...)
+	}
+
+	return key, interface{}(unique(combined))
+}
+
+func FnIntersection(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Intersection")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var lists [][]interface{}
+	if lists, ok = listOfLists(argsInterface); !ok || len(lists) < 1 {
+		return key, node //can't intersect non-arrays, passthru
+	}
+
+	others := []valueSet{}
+	for _, list := range lists[1:] {
+		others = append(others, newValueSet(list))
+	}
+
+	intersection := []interface{}{}
+	for _, value := range unique(lists[0]) {
+		inAll := true
+		for _, other := range others {
+			if !other.contains(value) {
+				inAll = false
+				break
+			}
+		}
+
+		if inAll {
+			intersection = append(intersection, value)
+		}
+	}
+
+	return key, interface{}(intersection)
+}
+
+func FnDifference(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Difference")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var lists [][]interface{}
+	if lists, ok = listOfLists(argsInterface); !ok || len(lists) < 1 {
+		return key, node //can't subtract non-arrays, passthru
+	}
+
+	excluded := valueSet{}
+	for _, list := range lists[1:] {
+		for _, value := range list {
+			excluded.add(value)
+		}
+	}
+
+	difference := []interface{}{}
+	for _, value := range unique(lists[0]) {
+		if !excluded.contains(value) {
+			difference = append(difference, value)
+		}
+	}
+
+	return key, interface{}(difference)
+}
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"condense/template"
+	"reflect"
+	"testing"
+)
+
+func TestFnSets_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnContains, "Fn::Contains", t)
+	testRule_Passthru_NonMatching(FnUnion, "Fn::Union", t)
+	testRule_Passthru_NonMatching(FnIntersection, "Fn::Intersection", t)
+	testRule_Passthru_NonMatching(FnDifference, "Fn::Difference", t)
+}
+
+func TestFnSets_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnContains, "Fn::Contains", t)
+	testRule_Passthru_NonArgsList(FnUnion, "Fn::Union", t)
+	testRule_Passthru_NonArgsList(FnIntersection, "Fn::Intersection", t)
+	testRule_Passthru_NonArgsList(FnDifference, "Fn::Difference", t)
+}
+
+func TestFnSets_Passthru_Unresolved(t *testing.T) {
+	unresolved := map[string]interface{}{"Ref": "Unresolved"}
+
+	rules := map[string]template.Rule{
+		"Fn::Contains":     FnContains,
+		"Fn::Union":        FnUnion,
+		"Fn::Intersection": FnIntersection,
+		"Fn::Difference":   FnDifference,
+	}
+
+	inputs := map[string][]interface{}{
+		"Fn::Contains": []interface{}{
+			[]interface{}{unresolved, "a"},
+			[]interface{}{[]interface{}{"a"}},
+		},
+		"Fn::Union": []interface{}{
+			[]interface{}{[]interface{}{"a"}, unresolved},
+		},
+		"Fn::Intersection": []interface{}{
+			[]interface{}{},
+			[]interface{}{unresolved, []interface{}{"a"}},
+		},
+		"Fn::Difference": []interface{}{
+			[]interface{}{},
+			[]interface{}{[]interface{}{"a"}, unresolved},
+		},
+	}
+
+	for fnName, fnInputs := range inputs {
+		for _, input := range fnInputs {
+			input := interface{}(map[string]interface{}{
+				fnName: input,
+			})
+
+			newKey, newNode := rules[fnName]([]interface{}{"x", "y"}, input)
+			if newKey != "y" {
+				t.Fatalf("%s modified the path (%v instead of %v)", fnName, newKey, "y")
+			}
+
+			if !reflect.DeepEqual(newNode, input) {
+				t.Fatalf("%s with unresolved arguments %v modified the data (%v instead of %v)", fnName, input, newNode, input)
+			}
+		}
+	}
+}
+
+func TestFnSets_Basic(t *testing.T) {
+	allowed := []interface{}{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+	denied := []interface{}{"172.16.0.0/12"}
+
+	rules := []template.Rule{
+		FnContains,
+		FnContains,
+		FnContains,
+		FnUnion,
+		FnIntersection,
+		FnDifference,
+		FnDifference,
+	}
+
+	inputs := []interface{}{
+		map[string]interface{}{"Fn::Contains": []interface{}{allowed, "10.0.0.0/8"}},
+		map[string]interface{}{"Fn::Contains": []interface{}{allowed, "8.8.8.8/32"}},
+		map[string]interface{}{"Fn::Contains": []interface{}{[]interface{}{1, map[string]interface{}{"a": 1}}, map[string]interface{}{"a": 1.0}}},
+		map[string]interface{}{"Fn::Union": []interface{}{
+			[]interface{}{"a", "b", "a"},
+			[]interface{}{"c", "b"},
+		}},
+		map[string]interface{}{"Fn::Intersection": []interface{}{
+			[]interface{}{float64(1), float64(2), float64(3), float64(2)},
+			[]interface{}{3, 2},
+			[]interface{}{float64(2), float64(3), float64(4)},
+		}},
+		map[string]interface{}{"Fn::Difference": []interface{}{allowed, denied}},
+		map[string]interface{}{"Fn::Difference": []interface{}{[]interface{}{"a", "a"}}},
+	}
+
+	expected := []interface{}{
+		true,
+		false,
+		true,
+		[]interface{}{"a", "b", "c"},
+		[]interface{}{float64(2), float64(3)},
+		[]interface{}{"10.0.0.0/8", "192.168.0.0/16"},
+		[]interface{}{"a"},
+	}
+
+	for i, input := range inputs {
+		newKey, newNode := rules[i]([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("Set function modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("Set function of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
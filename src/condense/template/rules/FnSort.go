@@ -0,0 +1,142 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fmt"
+	"sort"
+)
+
+// scalarsLess returns a less-than function for a list of keys, if the keys
+// are either all numbers or all strings.
+func scalarsLess(keys []interface{}) (func(i, j int) bool, bool) {
+	allNumbers, allStrings := true, true
+	for _, sortKey := range keys {
+		if _, ok := sortKey.(float64); !ok {
+			allNumbers = false
+		}
+
+		if _, ok := sortKey.(string); !ok {
+			allStrings = false
+		}
+	}
+
+	switch {
+	case allNumbers:
+		return func(i, j int) bool { return keys[i].(float64) < keys[j].(float64) }, true
+	case allStrings:
+		return func(i, j int) bool { return keys[i].(string) < keys[j].(string) }, true
+	}
+
+	return nil, false
+}
+
+type sortByKeys struct {
+	values []interface{}
+	keys   []interface{}
+	less   func(i, j int) bool
+}
+
+func (s sortByKeys) Len() int           { return len(s.values) }
+func (s sortByKeys) Less(i, j int) bool { return s.less(i, j) }
+func (s sortByKeys) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+func FnSort(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Sort")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	sorted := make([]interface{}, len(args))
+	copy(sorted, args)
+
+	var less func(i, j int) bool
+	if less, ok = scalarsLess(sorted); !ok {
+		return key, node //can't sort non-scalars or mixed types, passthru
+	}
+
+	sort.SliceStable(sorted, less)
+	return key, interface{}(sorted)
+}
+
+func MakeFnSortBy(sources *deepstack.DeepStack, templateRules *template.Rules) template.Rule {
+	return func(path []interface{}, node interface{}) (interface{}, interface{}) {
+		key := interface{}(nil)
+		if len(path) > 0 {
+			key = path[len(path)-1]
+		}
+
+		raw, ok := singleKey(node, "Fn::SortBy")
+		if !ok {
+			return key, node //passthru
+		}
+
+		args, ok := collectTemplateArgsBetween(path, raw, 3, 4, 2, 3, templateRules)
+		if !ok {
+			return key, node //passthru
+		}
+
+		var refNames []interface{}
+		if refNames, ok = parseRefNames(args[0]); !ok {
+			return key, node //passthru
+		}
+
+		var values []interface{}
+		if values, ok = args[1].([]interface{}); !ok {
+			return key, node //passthru
+		}
+
+		keyTemplate := interface{}(args[2])
+
+		descending := false
+		if len(args) == 4 {
+			if descending, ok = args[3].(bool); !ok {
+				return key, node //passthru
+			}
+		}
+
+		sorted := make([]interface{}, len(values))
+		copy(sorted, values)
+
+		sortKeys := make([]interface{}, len(values))
+		for deepIndex, value := range values {
+			deepPath := make([]interface{}, len(path)+1)
+			copy(deepPath, path)
+			deepPath[cap(deepPath)-1] = interface{}(deepIndex)
+
+			sources.Push(bindRefNames(refNames, float64(deepIndex), value))
+			_, sortKeys[deepIndex] = template.Walk(deepPath, keyTemplate, templateRules)
+			sources.PopDiscard()
+		}
+
+		var less func(i, j int) bool
+		if less, ok = scalarsLess(sortKeys); !ok {
+			if isUnresolved(sortKeys) {
+				return key, node //passthru (keys depend upon unbound values)
+			}
+
+			panic(fmt.Errorf("Fn::SortBy key template at '%s' did not resolve to all numbers or all strings (got %#v)", formatPath(path), sortKeys))
+		}
+
+		if descending {
+			ascending := less
+			less = func(i, j int) bool { return ascending(j, i) }
+		}
+
+		sort.Stable(sortByKeys{sorted, sortKeys, less})
+		return key, interface{}(sorted)
+	}
+}
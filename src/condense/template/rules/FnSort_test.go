@@ -0,0 +1,201 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"reflect"
+	"testing"
+)
+
+func TestFnSort_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnSort, "Fn::Sort", t)
+}
+
+func TestFnSort_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnSort, "Fn::Sort", t)
+}
+
+func TestFnSort_Passthru_MixedTypes(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{float64(1), "a"},
+		[]interface{}{"a", map[string]interface{}{"Ref": "Unresolved"}},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Sort": input,
+		})
+
+		newKey, newNode := FnSort([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnSort modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnSort of mixed types %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnSort_Basic(t *testing.T) {
+	inputs := [][]interface{}{
+		[]interface{}{float64(10), float64(9), float64(100)},
+		[]interface{}{"10", "9", "100"},
+		[]interface{}{},
+	}
+
+	expected := []interface{}{
+		[]interface{}{float64(9), float64(10), float64(100)},
+		[]interface{}{"10", "100", "9"},
+		[]interface{}{},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Sort": input,
+		})
+
+		newKey, newNode := FnSort([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnSort modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnSort of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func testMakeFnSortBy(stack deepstack.DeepStack, rules template.Rules) template.Rule {
+	return MakeFnSortBy(&stack, &rules)
+}
+
+func TestFnSortBy_Passthru_NonMatching(t *testing.T) {
+	fnSortBy := testMakeFnSortBy(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonMatching(fnSortBy, "Fn::SortBy", t)
+}
+
+func TestFnSortBy_Passthru_NonArgsList(t *testing.T) {
+	fnSortBy := testMakeFnSortBy(deepstack.DeepStack{}, template.Rules{})
+	testRule_Passthru_NonArgsList(fnSortBy, "Fn::SortBy", t)
+}
+
+func TestFnSortBy_Passthru_BadArguments(t *testing.T) {
+	fnSortBy := testMakeFnSortBy(deepstack.DeepStack{}, template.Rules{})
+
+	inputs := [][]interface{}{
+		[]interface{}{"$value", []interface{}{1, 2}},
+		[]interface{}{"$value", []interface{}{1, 2}, "aTemplate", true, "tooMany"},
+		[]interface{}{"$value", "nonList", "aTemplate"},
+		[]interface{}{"$value", []interface{}{1, 2}, "aTemplate", "nonBool"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::SortBy": input,
+		})
+
+		newKey, newNode := fnSortBy([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnSortBy modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnSortBy with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnSortBy_Panic_NonScalarKeys(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			// do nothing
+		}
+	}()
+
+	fnSortBy := testMakeFnSortBy(deepstack.DeepStack{}, template.Rules{})
+	input := interface{}(map[string]interface{}{
+		"Fn::SortBy": []interface{}{"$value", []interface{}{"a", "b"}, []interface{}{float64(1)}},
+	})
+
+	_, _ = fnSortBy([]interface{}{"x", "y"}, input)
+	t.Fatalf("FnSortBy with non-scalar keys did not panic")
+}
+
+func TestFnSortBy_Basic(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeFnGetAtt(&stack, &templateRules))
+
+	fnSortBy := MakeFnSortBy(&stack, &templateRules)
+
+	listeners := []interface{}{
+		map[string]interface{}{"name": "a", "priority": float64(20)},
+		map[string]interface{}{"name": "b", "priority": float64(10)},
+		map[string]interface{}{"name": "c", "priority": float64(20)},
+		map[string]interface{}{"name": "d", "priority": float64(5)},
+	}
+
+	priority := map[string]interface{}{"Fn::GetAtt": []interface{}{"$listener", "priority"}}
+	inputs := [][]interface{}{
+		[]interface{}{"$listener", listeners, priority},
+		[]interface{}{"$listener", listeners, priority, true},
+	}
+
+	expected := []interface{}{
+		[]interface{}{listeners[3], listeners[1], listeners[0], listeners[2]},
+		[]interface{}{listeners[0], listeners[2], listeners[1], listeners[3]},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::SortBy": input,
+		})
+
+		newKey, newNode := fnSortBy([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnSortBy modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnSortBy of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnSortBy_NestedOuterBinding(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	templateRules := template.Rules{}
+	templateRules.AttachEarly(MakeFnSortBy(&stack, &templateRules))
+	templateRules.AttachEarly(MakeFnWith(&stack, &templateRules))
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(MakeFnGetAtt(&stack, &templateRules))
+
+	values := []interface{}{
+		map[string]interface{}{"n": float64(2)},
+		map[string]interface{}{"n": float64(1)},
+	}
+
+	input := interface{}(map[string]interface{}{
+		"Fn::With": []interface{}{
+			map[string]interface{}{"$f": "n"},
+			map[string]interface{}{
+				"Fn::SortBy": []interface{}{
+					"$v",
+					values,
+					map[string]interface{}{"Fn::GetAtt": []interface{}{"$v", map[string]interface{}{"Ref": "$f"}}},
+				},
+			},
+		},
+	})
+
+	expected := interface{}([]interface{}{values[1], values[0]})
+	newKey, newNode := template.Walk([]interface{}{"x", "y"}, input, &templateRules)
+	if newKey != "y" {
+		t.Fatalf("FnSortBy modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnSortBy within Fn::With did not return the expected result (%#v instead of %#v)", newNode, expected)
+	}
+}
@@ -0,0 +1,77 @@
+package rules
+
+func FnSwitch(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Switch")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	if len(args) != 3 {
+		return key, node //passthru
+	}
+
+	if isIntrinsic(args[0]) {
+		return key, node //can't select on an unresolved value, passthru
+	}
+
+	var cases []interface{}
+	if cases, ok = args[1].([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	for _, caseInterface := range cases {
+		var casePair []interface{}
+		if casePair, ok = caseInterface.([]interface{}); !ok || len(casePair) != 2 {
+			return key, node //passthru
+		}
+
+		if isIntrinsic(casePair[0]) {
+			return key, node //can't compare against an unresolved case, passthru
+		}
+	}
+
+	value := hashValue(args[0])
+	for _, caseInterface := range cases {
+		casePair := caseInterface.([]interface{})
+		if hashValue(casePair[0]) == value {
+			return key, casePair[1]
+		}
+	}
+
+	return key, args[2]
+}
+
+func FnCoalesce(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argsInterface, ok := singleKey(node, "Fn::Coalesce")
+	if !ok {
+		return key, node //passthru
+	}
+
+	var args []interface{}
+	if args, ok = argsInterface.([]interface{}); !ok {
+		return key, node //passthru
+	}
+
+	for _, arg := range args {
+		if arg != nil && !isIntrinsic(arg) {
+			return key, arg
+		}
+	}
+
+	return key, node //nothing resolved, passthru
+}
@@ -0,0 +1,127 @@
+package rules
+
+import (
+	"condense/template"
+	"deepstack"
+	"fallbackmap"
+	"reflect"
+	"testing"
+)
+
+func TestFnSwitch_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnSwitch, "Fn::Switch", t)
+}
+
+func TestFnSwitch_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnSwitch, "Fn::Switch", t)
+}
+
+func TestFnSwitch_Passthru_BadArguments(t *testing.T) {
+	unresolved := map[string]interface{}{"Ref": "Unresolved"}
+
+	inputs := [][]interface{}{
+		[]interface{}{"prod", []interface{}{}},
+		[]interface{}{"prod", []interface{}{}, "default", "tooMany"},
+		[]interface{}{"prod", "nonList", "default"},
+		[]interface{}{"prod", []interface{}{"nonPair"}, "default"},
+		[]interface{}{"prod", []interface{}{[]interface{}{"prod", "a", "tooMany"}}, "default"},
+		[]interface{}{unresolved, []interface{}{[]interface{}{"prod", "a"}}, "default"},
+		[]interface{}{"prod", []interface{}{[]interface{}{unresolved, "a"}}, "default"},
+	}
+
+	for _, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Switch": input,
+		})
+
+		newKey, newNode := FnSwitch([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnSwitch modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("FnSwitch with bad arguments %v modified the data (%v instead of %v)", input, newNode, input)
+		}
+	}
+}
+
+func TestFnSwitch_Basic(t *testing.T) {
+	cases := []interface{}{
+		[]interface{}{"prod", "m5.large"},
+		[]interface{}{"staging", "t3.medium"},
+		[]interface{}{float64(1), "one"},
+	}
+
+	inputs := []interface{}{"prod", "staging", "dev", 1}
+	expected := []interface{}{"m5.large", "t3.medium", "t3.micro", "one"}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Switch": []interface{}{input, cases, "t3.micro"},
+		})
+
+		newKey, newNode := FnSwitch([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("FnSwitch modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnSwitch of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
+
+func TestFnCoalesce_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnCoalesce, "Fn::Coalesce", t)
+}
+
+func TestFnCoalesce_Passthru_NonArgsList(t *testing.T) {
+	testRule_Passthru_NonArgsList(FnCoalesce, "Fn::Coalesce", t)
+}
+
+func TestFnCoalesce_Passthru_NothingResolved(t *testing.T) {
+	input := interface{}(map[string]interface{}{
+		"Fn::Coalesce": []interface{}{nil, map[string]interface{}{"Ref": "Unresolved"}},
+	})
+
+	newKey, newNode := FnCoalesce([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnCoalesce modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, input) {
+		t.Fatalf("FnCoalesce with nothing resolved modified the data (%v instead of %v)", newNode, input)
+	}
+}
+
+func TestFnCoalesce_Basic(t *testing.T) {
+	stack := deepstack.DeepStack{}
+	stack.Push(fallbackmap.DeepMap(map[string]interface{}{"Bound": "boundValue"}))
+
+	templateRules := template.Rules{}
+	templateRules.Attach(MakeRef(&stack, &templateRules))
+	templateRules.Attach(FnCoalesce)
+
+	inputs := []interface{}{
+		[]interface{}{map[string]interface{}{"Ref": "Unbound"}, "default"},
+		[]interface{}{map[string]interface{}{"Ref": "Bound"}, "default"},
+		[]interface{}{nil, map[string]interface{}{"a": "one", "b": "two"}},
+	}
+
+	expected := []interface{}{
+		"default",
+		"boundValue",
+		map[string]interface{}{"a": "one", "b": "two"},
+	}
+
+	for i, input := range inputs {
+		input := interface{}(map[string]interface{}{
+			"Fn::Coalesce": input,
+		})
+
+		newNode := template.Process(input, &templateRules)
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("FnCoalesce of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// formatScalar converts a string, number or Boolean to its string form,
+// formatting numbers without truncation or exponents.
+func formatScalar(value interface{}) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return typed, true
+	case int:
+		return strconv.Itoa(typed), true
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(typed), true
+	}
+
+	return "", false
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case int, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case nil:
+		return "null"
+	}
+
+	return "unknown"
+}
+
+func conversionError(path []interface{}, fnName string, value interface{}) error {
+	return fmt.Errorf("%s at '%s' cannot convert %s %#v", fnName, formatPath(path), typeOf(value), value)
+}
+
+func FnTypeOf(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::TypeOf")
+	if !ok || isIntrinsic(argInterface) {
+		return key, node //passthru
+	}
+
+	return key, interface{}(typeOf(argInterface))
+}
+
+func FnToNumber(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::ToNumber")
+	if !ok || isIntrinsic(argInterface) {
+		return key, node //passthru
+	}
+
+	switch typed := argInterface.(type) {
+	case float64:
+		return key, interface{}(typed)
+	case int:
+		return key, interface{}(float64(typed))
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		if err == nil && !math.IsNaN(number) && !math.IsInf(number, 0) {
+			return key, interface{}(number)
+		}
+	case bool:
+		if typed {
+			return key, interface{}(float64(1))
+		}
+
+		return key, interface{}(float64(0))
+	}
+
+	panic(conversionError(path, "Fn::ToNumber", argInterface))
+}
+
+func FnToString(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::ToString")
+	if !ok || isIntrinsic(argInterface) {
+		return key, node //passthru
+	}
+
+	if formatted, ok := formatScalar(argInterface); ok {
+		return key, interface{}(formatted)
+	}
+
+	panic(conversionError(path, "Fn::ToString", argInterface))
+}
+
+func FnToBool(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::ToBool")
+	if !ok || isIntrinsic(argInterface) {
+		return key, node //passthru
+	}
+
+	switch typed := argInterface.(type) {
+	case bool:
+		return key, interface{}(typed)
+	case float64:
+		return key, interface{}(typed != 0)
+	case int:
+		return key, interface{}(typed != 0)
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(typed))
+		if err == nil {
+			return key, interface{}(parsed)
+		}
+	}
+
+	panic(conversionError(path, "Fn::ToBool", argInterface))
+}
+
+func FnToList(path []interface{}, node interface{}) (interface{}, interface{}) {
+	key := interface{}(nil)
+	if len(path) > 0 {
+		key = path[len(path)-1]
+	}
+
+	argInterface, ok := singleKey(node, "Fn::ToList")
+	if !ok || isIntrinsic(argInterface) {
+		return key, node //passthru
+	}
+
+	switch typed := argInterface.(type) {
+	case []interface{}:
+		return key, interface{}(typed)
+	case nil:
+		return key, interface{}([]interface{}{})
+	}
+
+	return key, interface{}([]interface{}{argInterface})
+}
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"condense/template"
+	"reflect"
+	"testing"
+)
+
+func TestFnTypes_Passthru_NonMatching(t *testing.T) {
+	testRule_Passthru_NonMatching(FnTypeOf, "Fn::TypeOf", t)
+	testRule_Passthru_NonMatching(FnToNumber, "Fn::ToNumber", t)
+	testRule_Passthru_NonMatching(FnToString, "Fn::ToString", t)
+	testRule_Passthru_NonMatching(FnToBool, "Fn::ToBool", t)
+	testRule_Passthru_NonMatching(FnToList, "Fn::ToList", t)
+}
+
+func TestFnTypes_Passthru_Unresolved(t *testing.T) {
+	rules := map[string]template.Rule{
+		"Fn::TypeOf":   FnTypeOf,
+		"Fn::ToNumber": FnToNumber,
+		"Fn::ToString": FnToString,
+		"Fn::ToBool":   FnToBool,
+		"Fn::ToList":   FnToList,
+	}
+
+	for fnName, rule := range rules {
+		input := interface{}(map[string]interface{}{
+			fnName: map[string]interface{}{"Ref": "Unresolved"},
+		})
+
+		newKey, newNode := rule([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("%s modified the path (%v instead of %v)", fnName, newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, input) {
+			t.Fatalf("%s of an unresolved value modified the data (%v instead of %v)", fnName, newNode, input)
+		}
+	}
+}
+
+func TestFnTypes_Panic_BadConversion(t *testing.T) {
+	inputs := []interface{}{
+		map[string]interface{}{"Fn::ToNumber": "three"},
+		map[string]interface{}{"Fn::ToNumber": []interface{}{}},
+		map[string]interface{}{"Fn::ToNumber": "NaN"},
+		map[string]interface{}{"Fn::ToNumber": "-Inf"},
+		map[string]interface{}{"Fn::ToNumber": "1e999"},
+		map[string]interface{}{"Fn::ToString": map[string]interface{}{"a": 1, "b": 2}},
+		map[string]interface{}{"Fn::ToBool": "maybe"},
+	}
+
+	rules := []template.Rule{FnToNumber, FnToNumber, FnToNumber, FnToNumber, FnToNumber, FnToString, FnToBool}
+
+	for i, input := range inputs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					// do nothing
+				}
+			}()
+
+			_, _ = rules[i]([]interface{}{"x", "y"}, input)
+			t.Fatalf("Converting %v did not panic", input)
+		}()
+	}
+}
+
+func TestFnTypes_Basic(t *testing.T) {
+	rules := []template.Rule{
+		FnTypeOf,
+		FnTypeOf,
+		FnTypeOf,
+		FnTypeOf,
+		FnToNumber,
+		FnToNumber,
+		FnToString,
+		FnToString,
+		FnToString,
+		FnToBool,
+		FnToBool,
+		FnToList,
+		FnToList,
+		FnToList,
+	}
+
+	inputs := []interface{}{
+		map[string]interface{}{"Fn::TypeOf": "3"},
+		map[string]interface{}{"Fn::TypeOf": float64(3)},
+		map[string]interface{}{"Fn::TypeOf": nil},
+		map[string]interface{}{"Fn::TypeOf": map[string]interface{}{"a": 1, "b": 2}},
+		map[string]interface{}{"Fn::ToNumber": " 3.5"},
+		map[string]interface{}{"Fn::ToNumber": true},
+		map[string]interface{}{"Fn::ToString": float64(1.5)},
+		map[string]interface{}{"Fn::ToString": float64(10000000)},
+		map[string]interface{}{"Fn::ToString": false},
+		map[string]interface{}{"Fn::ToBool": "true"},
+		map[string]interface{}{"Fn::ToBool": float64(0)},
+		map[string]interface{}{"Fn::ToList": "a"},
+		map[string]interface{}{"Fn::ToList": []interface{}{"a"}},
+		map[string]interface{}{"Fn::ToList": nil},
+	}
+
+	expected := []interface{}{
+		"string",
+		"number",
+		"null",
+		"object",
+		float64(3.5),
+		float64(1),
+		"1.5",
+		"10000000",
+		"false",
+		true,
+		false,
+		[]interface{}{"a"},
+		[]interface{}{"a"},
+		[]interface{}{},
+	}
+
+	for i, input := range inputs {
+		newKey, newNode := rules[i]([]interface{}{"x", "y"}, input)
+		if newKey != "y" {
+			t.Fatalf("Type function modified the path (%v instead of %v)", newKey, "y")
+		}
+
+		if !reflect.DeepEqual(newNode, expected[i]) {
+			t.Fatalf("Type function of %v did not return the expected result (%#v instead of %#v)", input, newNode, expected[i])
+		}
+	}
+}
@@ -1,9 +1,5 @@
 package rules
 
-import (
-	"reflect"
-)
-
 func FnUnique(path []interface{}, node interface{}) (interface{}, interface{}) {
 	key := interface{}(nil)
 	if len(path) > 0 {
@@ -20,20 +16,5 @@ func FnUnique(path []interface{}, node interface{}) (interface{}, interface{}) {
 		return key, node //passthru
 	}
 
-	var filtered []interface{}
-	for i := range args {
-		found := false
-		for j := range filtered {
-			if reflect.DeepEqual(&filtered[j], &args[i]) {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			filtered = append(filtered, args[i])
-		}
-	}
-
-	return key, interface{}(filtered)
+	return key, interface{}(unique(args))
 }
@@ -58,3 +58,20 @@ func TestFnUnique_Basic(t *testing.T) {
 		t.Fatalf("FnUnique as %#v did not result in the expected data (%#v instead of %#v)", input, newNode, expected)
 	}
 }
+
+func TestFnUnique_NumericEquality(t *testing.T) {
+	input := interface{}(map[string]interface{}{
+		"Fn::Unique": []interface{}{1, 1.0, float64(2), 2},
+	})
+
+	expected := []interface{}{1, float64(2)}
+
+	newKey, newNode := FnUnique([]interface{}{"x", "y"}, input)
+	if newKey != "y" {
+		t.Fatalf("FnUnique modified the path (%v instead of %v)", newKey, "y")
+	}
+
+	if !reflect.DeepEqual(newNode, expected) {
+		t.Fatalf("FnUnique as %#v did not treat equal numbers as duplicates (%#v instead of %#v)", input, newNode, expected)
+	}
+}
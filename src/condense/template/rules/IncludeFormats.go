@@ -0,0 +1,167 @@
+package rules
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type includeDecoder func(stream io.Reader) (interface{}, error)
+
+var includeDecoders = map[string]includeDecoder{
+	"json": decodeJson,
+	"yaml": decodeYaml,
+	"toml": decodeToml,
+	"csv":  decodeCsv,
+	"env":  decodeEnv,
+}
+
+var includeExtensions = map[string]string{
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".toml": "toml",
+	".csv":  "csv",
+	".env":  "env",
+}
+
+// includeFormat determines the format of an included file from its
+// extension, falling back to JSON for anything unrecognised.
+func includeFormat(filename string) string {
+	if format, ok := includeExtensions[strings.ToLower(filepath.Ext(filename))]; ok {
+		return format
+	}
+
+	return "json"
+}
+
+// normalizeDecoded converts the output of non-JSON decoders into the same
+// shapes that encoding/json would produce, so that rules see no difference.
+func normalizeDecoded(value interface{}) (interface{}, error) {
+	var err error
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			if result[fmt.Sprintf("%v", key)], err = normalizeDecoded(item); err != nil {
+				return nil, err
+			}
+		}
+		value = result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			if result[i], err = normalizeDecoded(item); err != nil {
+				return nil, err
+			}
+		}
+		value = result
+	}
+
+	var data []byte
+	if data, err = json.Marshal(value); err != nil {
+		return nil, err
+	}
+
+	normalized := interface{}(nil)
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func decodeJson(stream io.Reader) (interface{}, error) {
+	decoded := interface{}(nil)
+	err := json.NewDecoder(stream).Decode(&decoded)
+	return decoded, err
+}
+
+func decodeYaml(stream io.Reader) (interface{}, error) {
+	data, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := interface{}(nil)
+	if err = yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	return normalizeDecoded(decoded)
+}
+
+func decodeToml(stream io.Reader) (interface{}, error) {
+	decoded := map[string]interface{}{}
+	if _, err := toml.NewDecoder(stream).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return normalizeDecoded(decoded)
+}
+
+// decodeCsv produces an array of objects, keyed by the header row.
+func decodeCsv(stream io.Reader) (interface{}, error) {
+	records, err := csv.NewReader(stream).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []interface{}{}
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	header := records[0]
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// decodeEnv produces an object from KEY=VALUE lines, ignoring blank lines
+// and comments, and allowing an optional "export " prefix and quoting.
+func decodeEnv(stream io.Reader) (interface{}, error) {
+	result := map[string]interface{}{}
+
+	scanner := bufio.NewScanner(stream)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Missing '=' on line %d", lineNumber)
+		}
+
+		name := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid quoting on line %d: %s", lineNumber, err)
+			}
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+
+		result[name] = value
+	}
+
+	return result, scanner.Err()
+}
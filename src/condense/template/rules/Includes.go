@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type include struct {
+	absPath string
+	pointer string
+}
+
+// Includes tracks the chain of files currently being included, so that
+// relative paths can be resolved against the directory of the file which
+// contains them, and so that include cycles can be detected.
+type Includes struct {
+	// RelativeToCwd restores the legacy behaviour, where relative paths are
+	// resolved against the current working directory.
+	RelativeToCwd bool
+	MaxDepth      int
+	rootDir       string
+	files         []include
+}
+
+// NewIncludes creates an include stack whose top-level relative paths
+// resolve against rootDir.
+func NewIncludes(rootDir string, maxDepth int) *Includes {
+	return &Includes{
+		MaxDepth: maxDepth,
+		rootDir:  rootDir,
+		files:    []include{},
+	}
+}
+
+func (includes *Includes) describe(file include) string {
+	name := file.absPath
+	if relPath, err := filepath.Rel(includes.rootDir, file.absPath); err == nil && !strings.HasPrefix(relPath, "..") {
+		name = relPath
+	}
+
+	if file.pointer != "" {
+		name = name + "#" + file.pointer
+	}
+
+	return name
+}
+
+func (includes *Includes) chain(next include) string {
+	names := []string{}
+	for _, file := range includes.files {
+		names = append(names, includes.describe(file))
+	}
+
+	return strings.Join(append(names, includes.describe(next)), " -> ")
+}
+
+// Enter marks the start of processing (the part at pointer of) the file at
+// absPath, failing if that would form a cycle or exceed the maximum depth.
+func (includes *Includes) Enter(absPath string, pointer string) error {
+	next := include{absPath: absPath, pointer: pointer}
+	for _, file := range includes.files {
+		if file == next {
+			return fmt.Errorf("Include cycle detected: %s", includes.chain(next))
+		}
+	}
+
+	if len(includes.files) >= includes.MaxDepth {
+		return fmt.Errorf("Exceeded the maximum include depth of %d: %s", includes.MaxDepth, includes.chain(next))
+	}
+
+	includes.files = append(includes.files, next)
+	return nil
+}
+
+// Leave marks the end of processing the most recently entered file.
+func (includes *Includes) Leave() {
+	includes.files = includes.files[:len(includes.files)-1]
+}
+
+// Dir returns the directory of the file currently being processed.
+func (includes *Includes) Dir() string {
+	if len(includes.files) == 0 {
+		return includes.rootDir
+	}
+
+	return filepath.Dir(includes.files[len(includes.files)-1].absPath)
+}
+
+// Resolve returns the absolute path of filename, as referenced from the file
+// currently being processed.
+func (includes *Includes) Resolve(filename string) (string, error) {
+	if includes.RelativeToCwd || filepath.IsAbs(filename) {
+		return filepath.Abs(filename)
+	}
+
+	return filepath.Join(includes.Dir(), filename), nil
+}
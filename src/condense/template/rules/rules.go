@@ -1,5 +1,11 @@
 package rules
 
+import (
+	"condense/template"
+	"fmt"
+	"strings"
+)
+
 func isEqualString(candidate interface{}, test string) bool {
 	var ok bool
 	var candidateString string
@@ -11,6 +17,15 @@ func isEqualString(candidate interface{}, test string) bool {
 	return candidateString == test
 }
 
+func formatPath(path []interface{}) string {
+	parts := make([]string, len(path))
+	for i, part := range path {
+		parts[i] = fmt.Sprintf("%v", part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
 func singleKey(candidate interface{}, test string) (interface{}, bool) {
 	var ok bool
 	var candidateMap map[string]interface{}
@@ -28,6 +43,47 @@ func singleKey(candidate interface{}, test string) (interface{}, bool) {
 	return value, ok
 }
 
+// isUnresolved detects values which contain calls to a function which were
+// left unresolved, such as those depending upon bindings which are not yet
+// in place.
+func isUnresolved(candidate interface{}) bool {
+	if isIntrinsic(candidate) {
+		return true
+	}
+
+	switch typed := candidate.(type) {
+	case []interface{}:
+		for _, item := range typed {
+			if isUnresolved(item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range typed {
+			if isUnresolved(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isIntrinsic detects nodes which are calls to a function (ie: which were
+// left unresolved), rather than plain objects.
+func isIntrinsic(candidate interface{}) bool {
+	candidateMap, ok := candidate.(map[string]interface{})
+	if !ok || len(candidateMap) != 1 {
+		return false
+	}
+
+	for candidateKey := range candidateMap {
+		return candidateKey == "Ref" || strings.HasPrefix(candidateKey, "Fn::")
+	}
+
+	return false
+}
+
 type expectMoreCallback func(argsSoFar []interface{}) bool
 type processCallback func(argsSoFar []interface{}, arg interface{}) (skip bool, newNode interface{})
 
@@ -59,3 +115,48 @@ func collectArgs(candidate interface{}, expectMore expectMoreCallback, process p
 
 	return collected, true
 }
+
+// collectTemplateArgs collects exactly count arguments, processing the
+// leading ones. The trailing templateCount arguments are returned
+// unprocessed, as they are templates to be processed once their bindings
+// are in place.
+func collectTemplateArgs(path []interface{}, raw interface{}, count int, templateCount int, templateRules *template.Rules) ([]interface{}, bool) {
+	return collectTemplateArgsBetween(path, raw, count, count, count-templateCount, count, templateRules)
+}
+
+// collectTemplateArgsBetween collects between minCount and maxCount
+// arguments, processing all but those with an index in the range
+// [templateStart, templateEnd), which are returned unprocessed.
+func collectTemplateArgsBetween(path []interface{}, raw interface{}, minCount int, maxCount int, templateStart int, templateEnd int, templateRules *template.Rules) ([]interface{}, bool) {
+	var args []interface{}
+	var ok bool
+
+	if args, ok = raw.([]interface{}); !ok {
+		return nil, false // invalid args list
+	}
+
+	var collected []interface{}
+	for _, arg := range args {
+		// unconditionally process the argument, in case it needs to be skipped
+		key, node := template.Walk(path, arg, templateRules)
+		if skip, ok := key.(bool); ok && skip {
+			continue
+		}
+
+		if len(collected) >= maxCount {
+			return nil, false // invalid args list: too many arguments
+		}
+
+		if len(collected) >= templateStart && len(collected) < templateEnd {
+			node = arg // keep the unprocessed arg. It's a template.
+		}
+
+		collected = append(collected, node)
+	}
+
+	if len(collected) < minCount {
+		return nil, false // invalid args list: not enough arguments
+	}
+
+	return collected, true
+}
@@ -10,6 +10,13 @@ type Rules struct {
 	Depth []Rule
 }
 
+// Processed wraps a node which has already been processed, so that Walk
+// will return it as-is, rather than processing it again. This allows an
+// Early rule to process part of a node in advance.
+type Processed struct {
+	Node interface{}
+}
+
 func (r *Rules) AttachEarly(rule Rule) {
 	r.Early = append(r.Early, rule)
 }
@@ -63,6 +70,10 @@ func Walk(path []interface{}, node interface{}, rules *Rules) (newKey interface{
 		newKey = newPath[len(newPath)-1]
 	}
 
+	if processed, ok := newNode.(Processed); ok {
+		return newKey, processed.Node
+	}
+
 	newKey, newNode = eachRule(newPath, newNode, rules.Early)
 	if skip, ok := newKey.(bool); ok && skip {
 		return true, nil